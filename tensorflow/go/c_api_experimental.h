@@ -0,0 +1,46 @@
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+
+// Declarations of the functions of tensorflow/c/eager/c_api_experimental.h
+// used by the Go API, since that header does not compile as C (e.g. its
+// structs have default member initializers). The c_api_experimental_check
+// test in BUILD includes both headers, so that diverging declarations fail
+// to compile.
+
+#ifndef TENSORFLOW_GO_C_API_EXPERIMENTAL_H_
+#define TENSORFLOW_GO_C_API_EXPERIMENTAL_H_
+
+#include <stddef.h>
+
+#include "tensorflow/c/c_api.h"
+#include "tensorflow/c/eager/c_api.h"
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+extern void TFE_OpSetAttrValueProto(const TFE_Op* op, const char* attr_name,
+                                    const void* proto, size_t proto_len,
+                                    TF_Status* status);
+
+extern void TFE_ContextAsyncWait(TFE_Context* ctx, TF_Status* status);
+
+#ifdef __cplusplus
+} /* end extern "C" */
+#endif
+
+#endif  // TENSORFLOW_GO_C_API_EXPERIMENTAL_H_
@@ -0,0 +1,212 @@
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tensorflow
+
+// #include "tensorflow/c/c_api.h"
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/hdu-hh/tensorflow/tensorflow/go/pbs"
+	"google.golang.org/protobuf/proto"
+)
+
+// Callable executes a fixed signature of feeds, fetches and targets
+// in a [Session] with less overhead than [Session.Run].
+//
+// The feeds are sorted, the C arguments are prepared and the run options are
+// serialized only once when the Callable is made. A Callable is safe for
+// concurrent use by multiple goroutines.
+type Callable struct {
+	session   *Session
+	options   *pbs.CallableOptions
+	feeds     []C.TF_Output
+	feedTypes []DataType
+	feedOrder []int // feedOrder[i] is the index of the i-th sorted feed in the Call arguments
+	fetches   []C.TF_Output
+	targets   []*C.TF_Operation
+
+	mu         sync.RWMutex
+	runOptions *C.TF_Buffer
+	released   bool
+}
+
+// MakeCallable prepares the signature of feeds, fetches and targets
+// for repeated execution with [Callable.Call].
+//
+// See [Session.MakeCallableFromOptions] for specifying the signature
+// with a [pbs.CallableOptions] protocol buffer.
+func (s *Session) MakeCallable(feeds, fetches []Output, targets []*Operation) (*Callable, error) {
+	options := &pbs.CallableOptions{}
+	for _, o := range feeds {
+		options.Feed = append(options.Feed, fmt.Sprintf("%s:%d", o.Op.Name(), o.Index))
+	}
+	for _, o := range fetches {
+		options.Fetch = append(options.Fetch, fmt.Sprintf("%s:%d", o.Op.Name(), o.Index))
+	}
+	for _, op := range targets {
+		options.Target = append(options.Target, op.Name())
+	}
+	return s.newCallable(options, feeds, fetches, targets)
+}
+
+// MakeCallableFromOptions prepares the signature specified by the
+// [pbs.CallableOptions] for repeated execution with [Callable.Call].
+// The tensor and operation names of the options are resolved in graph g.
+// The run options of the options are applied to every call.
+//
+// Tensor connections and the placement of feeds
+// and fetches on devices are not supported yet.
+func (s *Session) MakeCallableFromOptions(g *Graph, options *pbs.CallableOptions) (*Callable, error) {
+	if len(options.GetTensorConnection()) > 0 || len(options.GetFeedDevices()) > 0 || len(options.GetFetchDevices()) > 0 {
+		return nil, errors.New("tensor connections and feed or fetch devices are not supported by Callable")
+	}
+	feeds := make([]Output, len(options.GetFeed()))
+	for i, name := range options.GetFeed() {
+		var err error
+		if feeds[i], err = g.outputByName(name); err != nil {
+			return nil, fmt.Errorf("invalid feed: %w", err)
+		}
+	}
+	fetches := make([]Output, len(options.GetFetch()))
+	for i, name := range options.GetFetch() {
+		var err error
+		if fetches[i], err = g.outputByName(name); err != nil {
+			return nil, fmt.Errorf("invalid fetch: %w", err)
+		}
+	}
+	targets := make([]*Operation, len(options.GetTarget()))
+	for i, name := range options.GetTarget() {
+		if targets[i] = g.Operation(name); targets[i] == nil {
+			return nil, fmt.Errorf("invalid target: operation %q not found", name)
+		}
+	}
+	return s.newCallable(proto.Clone(options).(*pbs.CallableOptions), feeds, fetches, targets)
+}
+
+func (s *Session) newCallable(options *pbs.CallableOptions, feeds, fetches []Output, targets []*Operation) (*Callable, error) {
+	c := &Callable{
+		session:   s,
+		options:   options,
+		feedTypes: make([]DataType, len(feeds)),
+		feedOrder: make([]int, len(feeds)),
+	}
+	// sort the feeds once like newCRunArgs does for every run
+	// and remember where to find the feed tensors in the call arguments
+	seen := make(map[Output]bool, len(feeds))
+	for i, o := range feeds {
+		if seen[o] {
+			return nil, fmt.Errorf("tensor %s:%d is fed more than once", o.Op.Name(), o.Index)
+		}
+		seen[o] = true
+		c.feedTypes[i] = o.DataType()
+		c.feedOrder[i] = i
+	}
+	args := newCRunArgs(nil, fetches, targets)
+	c.fetches, c.targets = args.fetches, args.targets
+	c.feeds = make([]C.TF_Output, len(feeds))
+	for i, o := range feeds {
+		c.feeds[i] = o.c()
+	}
+	sort.Sort(&callableFeedSort{feedsort{feeds: c.feeds}, c.feedOrder})
+
+	if ro := options.GetRunOptions(); ro != nil {
+		buf, err := proto.Marshal(ro)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RunOptions: %w", err)
+		}
+		c.runOptions = newBufferFromSlice(buf)
+	}
+	runtime.SetFinalizer(c, (*Callable).Release)
+	return c, nil
+}
+
+// callableFeedSort sorts the feeds like feedsort but keeps
+// track of their original order instead of their tensors.
+type callableFeedSort struct {
+	feedsort
+	order []int
+}
+
+func (f *callableFeedSort) Swap(i, j int) {
+	f.feeds[i], f.feeds[j] = f.feeds[j], f.feeds[i]
+	f.order[i], f.order[j] = f.order[j], f.order[i]
+}
+
+// Options returns the [pbs.CallableOptions] describing the signature of c.
+func (c *Callable) Options() *pbs.CallableOptions {
+	return proto.Clone(c.options).(*pbs.CallableOptions)
+}
+
+// Call executes the signature of c with the feeds provided in the order
+// of the feeds when c was made. On success, it returns the fetched Tensors
+// in the order of the fetches when c was made.
+func (c *Callable) Call(feeds []*Tensor) ([]*Tensor, error) {
+	if len(feeds) != len(c.feeds) {
+		return nil, fmt.Errorf("got %d feeds, want %d", len(feeds), len(c.feeds))
+	}
+	args := &cRunArgs{
+		feeds:        c.feeds,
+		feedTensors:  make([]*C.TF_Tensor, len(c.feeds)),
+		fetches:      c.fetches,
+		fetchTensors: make([]*C.TF_Tensor, len(c.fetches)),
+		targets:      c.targets,
+	}
+	for i, j := range c.feedOrder {
+		t := feeds[j]
+		if t == nil {
+			return nil, fmt.Errorf("feed %q is nil", c.options.Feed[j])
+		}
+		if dt := t.DataType(); dt != c.feedTypes[j] {
+			return nil, fmt.Errorf("feed %q has type %v, want %v", c.options.Feed[j], dt, c.feedTypes[j])
+		}
+		args.feedTensors[i] = t.c
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.released {
+		return nil, errors.New("callable has been released")
+	}
+	err := c.session.run(args, c.runOptions, nil)
+	runtime.KeepAlive(feeds)
+	if err != nil {
+		return nil, err
+	}
+	return args.toGo(), nil
+}
+
+// Release frees the resources of c after all pending calls have finished.
+// Calling c after it has been released fails. Release may be called
+// multiple times.
+func (c *Callable) Release() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.released {
+		return
+	}
+	c.released = true
+	if c.runOptions != nil {
+		C.TF_DeleteBuffer(c.runOptions)
+		c.runOptions = nil
+	}
+}
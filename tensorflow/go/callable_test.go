@@ -0,0 +1,203 @@
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tensorflow
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/hdu-hh/tensorflow/tensorflow/go/pbs"
+)
+
+// createCallableTestGraph returns a graph computing sum = a + b + c.
+func createCallableTestGraph() (g *Graph, feeds []Output, sum Output) {
+	g = NewGraph()
+	a := _Placeholder(g, "a", Int64)
+	b := _Placeholder(g, "b", Int64)
+	c := _Placeholder(g, "c", Int64)
+	sum = _Add(g, "sum", _Add(g, "ab", a, b), c)
+	return g, []Output{c, a, b}, sum
+}
+
+func TestCallable(t *testing.T) {
+	g, feeds, sum := createCallableTestGraph()
+	s, err := NewSession(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	callable, err := s.MakeCallable(feeds, []Output{sum, feeds[0]}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer callable.Release()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int64) {
+			defer wg.Done()
+			c, _ := NewTensor(i)
+			a, _ := NewTensor(10 * i)
+			b, _ := NewTensor(100 * i)
+			fetched, err := callable.Call([]*Tensor{c, a, b})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if got, want := fetched[0].Value().(int64), 111*i; got != want {
+				t.Errorf("Got sum %d, want %d", got, want)
+			}
+			if got, want := fetched[1].Value().(int64), i; got != want {
+				t.Errorf("Got c %d, want %d", got, want)
+			}
+		}(int64(i))
+	}
+	wg.Wait()
+
+	wantOptions := &pbs.CallableOptions{Feed: []string{"c:0", "a:0", "b:0"}, Fetch: []string{"sum:0", "c:0"}}
+	if got := callable.Options(); !reflect.DeepEqual(got.Feed, wantOptions.Feed) || !reflect.DeepEqual(got.Fetch, wantOptions.Fetch) {
+		t.Errorf("Got options %v, want %v", got, wantOptions)
+	}
+
+	wrongType, _ := NewTensor(int32(1))
+	if _, err := callable.Call([]*Tensor{wrongType, wrongType, wrongType}); err == nil {
+		t.Error("calling with wrongly typed feeds must fail")
+	}
+	if _, err := callable.Call(nil); err == nil {
+		t.Error("calling with missing feeds must fail")
+	}
+	callable.Release()
+	one, _ := NewTensor(int64(1))
+	if _, err := callable.Call([]*Tensor{one, one, one}); err == nil {
+		t.Error("calling a released callable must fail")
+	}
+}
+
+func TestCallableFromOptions(t *testing.T) {
+	g, _, _ := createCallableTestGraph()
+	s, err := NewSession(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	options := &pbs.CallableOptions{
+		Feed:       []string{"a", "b:0", "c:0"},
+		Fetch:      []string{"sum:0"},
+		RunOptions: &pbs.RunOptions{TimeoutInMs: 60000},
+	}
+	callable, err := s.MakeCallableFromOptions(g, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer callable.Release()
+	a, _ := NewTensor(int64(1))
+	b, _ := NewTensor(int64(2))
+	c, _ := NewTensor(int64(3))
+	fetched, err := callable.Call([]*Tensor{a, b, c})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fetched[0].Value().(int64), int64(6); got != want {
+		t.Errorf("Got %d, want %d", got, want)
+	}
+
+	for _, invalid := range []*pbs.CallableOptions{
+		{Feed: []string{"missing:0"}},
+		{Fetch: []string{"sum:1"}},
+		{Fetch: []string{"sum:x"}},
+		{Target: []string{"missing"}},
+		{Feed: []string{"a:0", "a:0"}},
+	} {
+		if _, err := s.MakeCallableFromOptions(g, invalid); err == nil {
+			t.Errorf("MakeCallableFromOptions(%v) must fail", invalid)
+		}
+	}
+}
+
+func benchmarkSumFeeds(b *testing.B) []*Tensor {
+	feeds := make([]*Tensor, 3)
+	for i := range feeds {
+		var err error
+		if feeds[i], err = NewTensor(int64(i)); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return feeds
+}
+
+func BenchmarkSessionRunSum(b *testing.B) {
+	g, feeds, sum := createCallableTestGraph()
+	s, err := NewSession(g, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer s.Close()
+	tensors := benchmarkSumFeeds(b)
+	feedMap := FeedMap{}
+	for i, o := range feeds {
+		feedMap[o] = tensors[i]
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.Run(feedMap, []Output{sum}, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCallableSum(b *testing.B) {
+	g, feeds, sum := createCallableTestGraph()
+	s, err := NewSession(g, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer s.Close()
+	callable, err := s.MakeCallable(feeds, []Output{sum}, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer callable.Release()
+	tensors := benchmarkSumFeeds(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := callable.Call(tensors); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func ExampleCallable() {
+	g, feeds, sum := createCallableTestGraph()
+	s, _ := NewSession(g, nil)
+	defer s.Close()
+	callable, _ := s.MakeCallable(feeds, []Output{sum}, nil)
+	defer callable.Release()
+	for i := int64(1); i <= 3; i++ {
+		c, _ := NewTensor(i)
+		a, _ := NewTensor(i * 10)
+		b, _ := NewTensor(i * 100)
+		fetched, _ := callable.Call([]*Tensor{c, a, b})
+		fmt.Println(fetched[0].Value())
+	}
+	// Output:
+	// 111
+	// 222
+	// 333
+}
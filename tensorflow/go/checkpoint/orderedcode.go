@@ -0,0 +1,103 @@
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkpoint
+
+import (
+	"encoding/binary"
+	"math/bits"
+
+	"github.com/hdu-hh/tensorflow/tensorflow/go/pbs"
+)
+
+// This file ports the parts of tensorflow/core/lib/strings/ordered_code.cc
+// needed to encode the keys of tensor slices.
+
+// sliceKey returns the key of the slice of the tensor with the given name,
+// which is the encoding of 0, the name, the number of dimensions and the
+// start and length of each extent. The length of a full extent is -1.
+func sliceKey(name string, slice *pbs.TensorSliceProto) string {
+	var b []byte
+	b = appendNumIncreasing(b, 0)
+	b = appendString(b, name)
+	b = appendNumIncreasing(b, uint64(len(slice.GetExtent())))
+	for _, extent := range slice.GetExtent() {
+		length := int64(-1)
+		if _, ok := extent.GetHasLength().(*pbs.TensorSliceProto_Extent_Length); ok {
+			length = extent.GetLength()
+		}
+		b = appendSignedNumIncreasing(b, extent.GetStart())
+		b = appendSignedNumIncreasing(b, length)
+	}
+	return string(b)
+}
+
+// appendNumIncreasing appends the number of significant bytes followed by
+// these bytes in big-endian order.
+func appendNumIncreasing(b []byte, v uint64) []byte {
+	n := (bits.Len64(v) + 7) / 8
+	b = append(b, byte(n))
+	for i := n - 1; i >= 0; i-- {
+		b = append(b, byte(v>>(8*i)))
+	}
+	return b
+}
+
+// appendString appends the string with escaped 0x00 and 0xff bytes
+// followed by the terminator 0x00 0x01.
+func appendString(b []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case 0x00:
+			b = append(b, 0x00, 0xff)
+		case 0xff:
+			b = append(b, 0xff, 0x00)
+		default:
+			b = append(b, c)
+		}
+	}
+	return append(b, 0x00, 0x01)
+}
+
+// lengthToHeaderBits are the bits marking the length of the encoding of a
+// signed number in its first two bytes.
+var lengthToHeaderBits = [11][2]byte{
+	{0, 0}, {0x80, 0}, {0xc0, 0}, {0xe0, 0}, {0xf0, 0},
+	{0xf8, 0}, {0xfc, 0}, {0xfe, 0}, {0xff, 0}, {0xff, 0x80}, {0xff, 0xc0},
+}
+
+// appendSignedNumIncreasing appends the signed number in big-endian order
+// using as many bytes as needed for its bits, its sign bit and its length,
+// which is marked by leading bits.
+func appendSignedNumIncreasing(b []byte, v int64) []byte {
+	x := uint64(v)
+	if v < 0 {
+		x = ^x
+	}
+	if x < 64 {
+		return append(b, lengthToHeaderBits[1][0]^byte(v))
+	}
+	var buf [10]byte
+	if v < 0 {
+		buf[0], buf[1] = 0xff, 0xff
+	}
+	binary.BigEndian.PutUint64(buf[2:], uint64(v))
+	n := (bits.Len64(x) + 7) / 7
+	begin := buf[len(buf)-n:]
+	begin[0] ^= lengthToHeaderBits[n][0]
+	begin[1] ^= lengthToHeaderBits[n][1]
+	return append(b, begin...)
+}
@@ -0,0 +1,402 @@
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package checkpoint reads TensorFlow checkpoints in the tensor bundle
+// format without a session, e.g. the variables of a SavedModel.
+//
+// A checkpoint with the prefix "path/model.ckpt" consists of the index
+// "path/model.ckpt.index", an SSTable mapping the tensor names to
+// [pbs.BundleEntryProto] entries, and the data shards
+// "path/model.ckpt.data-00000-of-00002" etc. which store the tensor
+// contents. Variables saved in slices (partitioned variables) are assembled
+// into full tensors.
+package checkpoint
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	tf "github.com/hdu-hh/tensorflow/tensorflow/go"
+	"github.com/hdu-hh/tensorflow/tensorflow/go/pbs"
+	"google.golang.org/protobuf/proto"
+)
+
+// Reader reads the tensors of a checkpoint.
+// It is safe for concurrent use by multiple goroutines.
+type Reader struct {
+	prefix  string
+	header  *pbs.BundleHeaderProto
+	entries map[string]*pbs.BundleEntryProto
+
+	mu     sync.Mutex
+	shards []*os.File // opened on demand
+}
+
+// Open reads the index of the checkpoint with the given prefix.
+func Open(prefix string) (*Reader, error) {
+	f, err := os.Open(prefix + ".index")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	r := &Reader{prefix: prefix, entries: make(map[string]*pbs.BundleEntryProto)}
+	err = readTable(f, info.Size(), func(key string, value []byte) error {
+		if key == "" {
+			r.header = new(pbs.BundleHeaderProto)
+			return proto.Unmarshal(value, r.header)
+		}
+		entry := new(pbs.BundleEntryProto)
+		if err := proto.Unmarshal(value, entry); err != nil {
+			return fmt.Errorf("invalid entry %q: %w", key, err)
+		}
+		r.entries[key] = entry
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %q: %w", f.Name(), err)
+	}
+	if r.header == nil {
+		return nil, fmt.Errorf("%q has no bundle header", f.Name())
+	}
+	if r.header.GetEndianness() != pbs.BundleHeaderProto_LITTLE {
+		return nil, fmt.Errorf("%q has unsupported big-endian tensors", f.Name())
+	}
+	r.shards = make([]*os.File, r.header.GetNumShards())
+	return r, nil
+}
+
+// Close closes the data files of the checkpoint and returns the first error.
+func (r *Reader) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var err error
+	for i, f := range r.shards {
+		if f != nil {
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			r.shards[i] = nil
+		}
+	}
+	return err
+}
+
+// Header returns the header of the checkpoint.
+func (r *Reader) Header() *pbs.BundleHeaderProto {
+	return r.header
+}
+
+// Names returns the sorted names of the tensors in the checkpoint.
+func (r *Reader) Names() []string {
+	names := make([]string, 0, len(r.entries))
+	for key := range r.entries {
+		if !strings.HasPrefix(key, "\x00") { // keys of slices
+			names = append(names, key)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Entry returns the entry of the tensor with the given name, which describes
+// its type and shape, or nil if there is no such tensor.
+func (r *Reader) Entry(name string) *pbs.BundleEntryProto {
+	if strings.HasPrefix(name, "\x00") {
+		return nil
+	}
+	return r.entries[name]
+}
+
+// Tensor reads the tensor with the given name and verifies its checksum.
+func (r *Reader) Tensor(name string) (*tf.Tensor, error) {
+	entry := r.Entry(name)
+	if entry == nil {
+		return nil, fmt.Errorf("tensor %q not found in checkpoint %q", name, r.prefix)
+	}
+	dt, shape := tf.DataType(entry.GetDtype()), shapeFromProto(entry.GetShape())
+	if len(entry.GetSlices()) == 0 {
+		return r.tensor(name, entry)
+	}
+
+	full, err := newTensorData(dt, shape)
+	if err != nil {
+		return nil, err
+	}
+	for _, slice := range entry.GetSlices() {
+		key := sliceKey(name, slice)
+		sliceEntry, ok := r.entries[key]
+		if !ok {
+			return nil, fmt.Errorf("slice %v of tensor %q not found", slice.GetExtent(), name)
+		}
+		start, length, err := sliceExtents(slice, shape)
+		if err != nil {
+			return nil, fmt.Errorf("invalid slice of tensor %q: %w", name, err)
+		}
+		data, err := r.read(name, sliceEntry)
+		if err != nil {
+			return nil, err
+		}
+		if err := full.copySlice(data, start, length); err != nil {
+			return nil, fmt.Errorf("invalid slice %v of tensor %q: %w", slice.GetExtent(), name, err)
+		}
+	}
+	return full.tensor()
+}
+
+func (r *Reader) tensor(name string, entry *pbs.BundleEntryProto) (*tf.Tensor, error) {
+	data, err := r.read(name, entry)
+	if err != nil {
+		return nil, err
+	}
+	return data.tensor()
+}
+
+// read reads and verifies the data of an entry.
+func (r *Reader) read(name string, entry *pbs.BundleEntryProto) (*tensorData, error) {
+	f, err := r.shard(int(entry.GetShardId()))
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, entry.GetSize())
+	if _, err := f.ReadAt(b, entry.GetOffset()); err != nil {
+		return nil, fmt.Errorf("failed to read tensor %q: %w", name, err)
+	}
+	dt, shape := tf.DataType(entry.GetDtype()), shapeFromProto(entry.GetShape())
+	var crc uint32
+	data := &tensorData{dataType: dt, shape: shape}
+	if dt == tf.String {
+		data.strings, crc, err = decodeStrings(b, numElements(shape))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tensor %q: %w", name, err)
+		}
+	} else {
+		data.bytes, crc = b, crc32.Checksum(b, crc32cTable)
+	}
+	if crc != unmaskCRC(entry.GetCrc32C()) {
+		return nil, fmt.Errorf("checksum mismatch of tensor %q", name)
+	}
+	return data, nil
+}
+
+// shard returns the data file of a shard.
+func (r *Reader) shard(id int) (*os.File, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if id < 0 || id >= len(r.shards) {
+		return nil, fmt.Errorf("invalid shard %d of checkpoint %q", id, r.prefix)
+	}
+	if r.shards[id] == nil {
+		f, err := os.Open(fmt.Sprintf("%s.data-%05d-of-%05d", r.prefix, id, len(r.shards)))
+		if err != nil {
+			return nil, err
+		}
+		r.shards[id] = f
+	}
+	return r.shards[id], nil
+}
+
+// decodeStrings decodes the varint64 lengths of the strings, the masked
+// checksum of the lengths and the concatenated strings. It also returns
+// the checksum of the data which covers the lengths as uint32 values if
+// possible or as uint64 values otherwise.
+func decodeStrings(b []byte, n int64) ([]string, uint32, error) {
+	errCorrupt := errors.New("corrupt string tensor")
+	lengths := make([]uint64, n)
+	var crc uint32
+	var buf [8]byte
+	for i := range lengths {
+		length, m := binary.Uvarint(b)
+		if m <= 0 {
+			return nil, 0, errCorrupt
+		}
+		lengths[i], b = length, b[m:]
+		if length <= 0xffffffff {
+			binary.LittleEndian.PutUint32(buf[:], uint32(length))
+			crc = crc32.Update(crc, crc32cTable, buf[:4])
+		} else {
+			binary.LittleEndian.PutUint64(buf[:], length)
+			crc = crc32.Update(crc, crc32cTable, buf[:])
+		}
+	}
+	if len(b) < 4 {
+		return nil, 0, errCorrupt
+	}
+	if unmaskCRC(binary.LittleEndian.Uint32(b)) != crc {
+		return nil, 0, errors.New("checksum mismatch of string lengths")
+	}
+	crc = crc32.Update(crc, crc32cTable, b[:4])
+	b = b[4:]
+	strs := make([]string, n)
+	for i, length := range lengths {
+		if length > uint64(len(b)) {
+			return nil, 0, errCorrupt
+		}
+		strs[i], b = string(b[:length]), b[length:]
+		crc = crc32.Update(crc, crc32cTable, []byte(strs[i]))
+	}
+	return strs, crc, nil
+}
+
+// tensorData holds the contents of a tensor, either strings or the bytes of
+// the elements.
+type tensorData struct {
+	dataType tf.DataType
+	shape    []int64
+	strings  []string
+	bytes    []byte
+}
+
+func newTensorData(dt tf.DataType, shape []int64) (*tensorData, error) {
+	data := &tensorData{dataType: dt, shape: shape}
+	if dt == tf.String {
+		data.strings = make([]string, numElements(shape))
+		return data, nil
+	}
+	size, err := elementSize(dt)
+	if err != nil {
+		return nil, err
+	}
+	data.bytes = make([]byte, size*numElements(shape))
+	return data, nil
+}
+
+func (d *tensorData) tensor() (*tf.Tensor, error) {
+	if d.dataType != tf.String {
+		return tf.NewTensorFromBytes(d.dataType, d.shape, d.bytes)
+	}
+	t, err := tf.NewTensor(d.strings)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.Reshape(d.shape); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// copySlice copies the contents of a slice with the given start indices and
+// lengths into the tensor.
+func (d *tensorData) copySlice(src *tensorData, start, length []int64) error {
+	if src.dataType != d.dataType {
+		return fmt.Errorf("slice has type %v instead of %v", src.dataType, d.dataType)
+	}
+	if numElements(src.shape) != numElements(length) {
+		return fmt.Errorf("slice has shape %v instead of %v", src.shape, length)
+	}
+	elemSize := int64(1)
+	if d.dataType != tf.String {
+		if n := numElements(d.shape); n > 0 {
+			elemSize = int64(len(d.bytes)) / n
+		}
+	}
+	rank := len(d.shape)
+	if rank == 0 {
+		d.copyElements(src, 0, 0, 1, elemSize)
+		return nil
+	}
+	// copy the contiguous runs in the last dimension
+	run := length[rank-1]
+	idx := make([]int64, rank-1)
+	for srcOffset := int64(0); srcOffset < numElements(length); srcOffset += run {
+		dstOffset := int64(0)
+		for i, n := range d.shape {
+			dstOffset *= n
+			if i < rank-1 {
+				dstOffset += start[i] + idx[i]
+			} else {
+				dstOffset += start[i]
+			}
+		}
+		d.copyElements(src, dstOffset, srcOffset, run, elemSize)
+		for i := rank - 2; i >= 0; i-- {
+			if idx[i]++; idx[i] < length[i] {
+				break
+			}
+			idx[i] = 0
+		}
+	}
+	return nil
+}
+
+func (d *tensorData) copyElements(src *tensorData, dstOffset, srcOffset, n, elemSize int64) {
+	if d.dataType == tf.String {
+		copy(d.strings[dstOffset:dstOffset+n], src.strings[srcOffset:])
+	} else {
+		copy(d.bytes[dstOffset*elemSize:(dstOffset+n)*elemSize], src.bytes[srcOffset*elemSize:])
+	}
+}
+
+// sliceExtents returns the start indices and lengths of the slice of a
+// tensor with the given shape.
+func sliceExtents(slice *pbs.TensorSliceProto, shape []int64) (start, length []int64, err error) {
+	if len(slice.GetExtent()) != len(shape) {
+		return nil, nil, fmt.Errorf("slice of rank %d for shape %v", len(slice.GetExtent()), shape)
+	}
+	start, length = make([]int64, len(shape)), make([]int64, len(shape))
+	for i, extent := range slice.GetExtent() {
+		start[i], length[i] = extent.GetStart(), shape[i]-extent.GetStart()
+		if _, ok := extent.GetHasLength().(*pbs.TensorSliceProto_Extent_Length); ok && extent.GetLength() >= 0 {
+			length[i] = extent.GetLength()
+		}
+		if start[i] < 0 || length[i] < 0 || start[i]+length[i] > shape[i] {
+			return nil, nil, fmt.Errorf("extent %v out of range for shape %v", extent, shape)
+		}
+	}
+	return start, length, nil
+}
+
+func shapeFromProto(pb *pbs.TensorShapeProto) []int64 {
+	shape := make([]int64, len(pb.GetDim()))
+	for i, dim := range pb.GetDim() {
+		shape[i] = dim.GetSize()
+	}
+	return shape
+}
+
+func numElements(shape []int64) int64 {
+	n := int64(1)
+	for _, d := range shape {
+		n *= d
+	}
+	return n
+}
+
+// elementSize returns the size of the elements of numeric types.
+func elementSize(dt tf.DataType) (int64, error) {
+	switch dt {
+	case tf.Bool, tf.Int8, tf.Uint8, tf.Qint8, tf.Quint8, tf.Float8e5m2, tf.Float8e4m3fn:
+		return 1, nil
+	case tf.Int16, tf.Uint16, tf.Qint16, tf.Quint16, tf.Half, tf.Bfloat16:
+		return 2, nil
+	case tf.Int32, tf.Uint32, tf.Qint32, tf.Float:
+		return 4, nil
+	case tf.Int64, tf.Uint64, tf.Double, tf.Complex64:
+		return 8, nil
+	case tf.Complex128:
+		return 16, nil
+	}
+	return 0, fmt.Errorf("tensors of type %v are not supported", dt)
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkpoint
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hdu-hh/tensorflow/tensorflow/go/pbs"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestReadSavedModelVariables(t *testing.T) {
+	r, err := Open("../testdata/saved_model/half_plus_two/00000123/variables/variables")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if got, want := r.Names(), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got names %v, want %v", got, want)
+	}
+	for name, want := range map[string]float32{"a": 0.5, "b": 2, "c": 3} {
+		tensor, err := r.Tensor(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := tensor.Value(); got != want {
+			t.Errorf("Got %s = %v, want %v", name, got, want)
+		}
+	}
+	if _, err := r.Tensor("d"); err == nil {
+		t.Errorf("Reading a missing tensor did not fail")
+	}
+}
+
+// bundleTensor is a tensor written by writeBundle.
+type bundleTensor struct {
+	key   string
+	entry *pbs.BundleEntryProto
+	data  []byte
+}
+
+// writeBundle writes a checkpoint with a single data shard.
+func writeBundle(t *testing.T, prefix string, tensors []bundleTensor) {
+	t.Helper()
+	sort.Slice(tensors, func(i, j int) bool { return tensors[i].key < tensors[j].key })
+	var data []byte
+	header := pbs.MustMarshal(&pbs.BundleHeaderProto{NumShards: 1})
+	entries := []bundleTensor{{key: "", data: header}}
+	for _, tensor := range tensors {
+		entry := proto.Clone(tensor.entry).(*pbs.BundleEntryProto)
+		if len(entry.GetSlices()) == 0 {
+			entry.Offset, entry.Size = int64(len(data)), int64(len(tensor.data))
+			if entry.Crc32C == 0 {
+				entry.Crc32C = maskCRC(crc32.Checksum(tensor.data, crc32cTable))
+			}
+			data = append(data, tensor.data...)
+		}
+		entries = append(entries, bundleTensor{key: tensor.key, data: pbs.MustMarshal(entry)})
+	}
+	if err := os.WriteFile(prefix+".data-00000-of-00001", data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var index bytes.Buffer
+	writeBlock := func(entries []bundleTensor) []byte {
+		var block []byte
+		for _, e := range entries {
+			block = binary.AppendUvarint(block, 0)
+			block = binary.AppendUvarint(block, uint64(len(e.key)))
+			block = binary.AppendUvarint(block, uint64(len(e.data)))
+			block = append(append(block, e.key...), e.data...)
+		}
+		block = binary.LittleEndian.AppendUint32(block, 0)
+		block = binary.LittleEndian.AppendUint32(block, 1)
+		handle := binary.AppendUvarint(nil, uint64(index.Len()))
+		handle = binary.AppendUvarint(handle, uint64(len(block)))
+		crc := crc32.Update(crc32.Checksum(block, crc32cTable), crc32cTable, []byte{noCompression})
+		index.Write(block)
+		index.WriteByte(noCompression)
+		binary.Write(&index, binary.LittleEndian, maskCRC(crc))
+		return handle
+	}
+	dataHandle := writeBlock(entries)
+	metaHandle := writeBlock(nil)
+	indexHandle := writeBlock([]bundleTensor{{key: "\xff", data: dataHandle}})
+	footer := append(metaHandle, indexHandle...)
+	footer = append(footer, make([]byte, 2*blockHandleMaxSize-len(footer))...)
+	footer = binary.LittleEndian.AppendUint64(footer, tableMagic)
+	index.Write(footer)
+	if err := os.WriteFile(prefix+".index", index.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func float32Bytes(values ...float32) []byte {
+	var b []byte
+	for _, v := range values {
+		b = binary.LittleEndian.AppendUint32(b, math.Float32bits(v))
+	}
+	return b
+}
+
+// stringEntry returns the data and entry of a string tensor. Its checksum
+// covers the lengths as uint32 values instead of their varint encoding.
+func stringEntry(shape *pbs.TensorShapeProto, values ...string) (*pbs.BundleEntryProto, []byte) {
+	var b, crcBytes []byte
+	for _, v := range values {
+		b = binary.AppendUvarint(b, uint64(len(v)))
+		crcBytes = binary.LittleEndian.AppendUint32(crcBytes, uint32(len(v)))
+	}
+	lengthsCRC := binary.LittleEndian.AppendUint32(nil, maskCRC(crc32.Checksum(crcBytes, crc32cTable)))
+	b = append(b, lengthsCRC...)
+	crcBytes = append(crcBytes, lengthsCRC...)
+	for _, v := range values {
+		b = append(b, v...)
+		crcBytes = append(crcBytes, v...)
+	}
+	return &pbs.BundleEntryProto{
+		Dtype:  pbs.DataType_DT_STRING,
+		Shape:  shape,
+		Crc32C: maskCRC(crc32.Checksum(crcBytes, crc32cTable)),
+	}, b
+}
+
+func shapeProto(dims ...int64) *pbs.TensorShapeProto {
+	shape := &pbs.TensorShapeProto{}
+	for _, d := range dims {
+		shape.Dim = append(shape.Dim, &pbs.TensorShapeProto_Dim{Size: d})
+	}
+	return shape
+}
+
+func TestReadSlicesAndStrings(t *testing.T) {
+	// rows of a 2x3 matrix saved as slices
+	row := func(i int64) *pbs.TensorSliceProto {
+		return &pbs.TensorSliceProto{Extent: []*pbs.TensorSliceProto_Extent{
+			{Start: i, HasLength: &pbs.TensorSliceProto_Extent_Length{Length: 1}},
+			{},
+		}}
+	}
+	// columns of a 2x2 string matrix
+	column := func(i int64) *pbs.TensorSliceProto {
+		return &pbs.TensorSliceProto{Extent: []*pbs.TensorSliceProto_Extent{
+			{},
+			{Start: i, HasLength: &pbs.TensorSliceProto_Extent_Length{Length: 1}},
+		}}
+	}
+	strsEntry, strs := stringEntry(shapeProto(3), "x", "", "yz")
+	column0Entry, column0 := stringEntry(shapeProto(2, 1), "a", "c")
+	column1Entry, column1 := stringEntry(shapeProto(2, 1), "b", "d")
+	prefix := filepath.Join(t.TempDir(), "model.ckpt")
+	writeBundle(t, prefix, []bundleTensor{
+		{key: "w", entry: &pbs.BundleEntryProto{
+			Dtype: pbs.DataType_DT_FLOAT, Shape: shapeProto(2, 3), Slices: []*pbs.TensorSliceProto{row(0), row(1)},
+		}},
+		{key: sliceKey("w", row(0)), entry: &pbs.BundleEntryProto{Dtype: pbs.DataType_DT_FLOAT, Shape: shapeProto(1, 3)}, data: float32Bytes(1, 2, 3)},
+		{key: sliceKey("w", row(1)), entry: &pbs.BundleEntryProto{Dtype: pbs.DataType_DT_FLOAT, Shape: shapeProto(1, 3)}, data: float32Bytes(4, 5, 6)},
+		{key: "s", entry: strsEntry, data: strs},
+		{key: "m", entry: &pbs.BundleEntryProto{
+			Dtype: pbs.DataType_DT_STRING, Shape: shapeProto(2, 2), Slices: []*pbs.TensorSliceProto{column(1), column(0)},
+		}},
+		{key: sliceKey("m", column(0)), entry: column0Entry, data: column0},
+		{key: sliceKey("m", column(1)), entry: column1Entry, data: column1},
+		{key: "bad", entry: &pbs.BundleEntryProto{Dtype: pbs.DataType_DT_FLOAT, Shape: shapeProto(), Crc32C: 1}, data: float32Bytes(1)},
+	})
+
+	r, err := Open(prefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if got, want := r.Names(), []string{"bad", "m", "s", "w"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got names %v, want %v", got, want)
+	}
+	tests := map[string]interface{}{
+		"w": [][]float32{{1, 2, 3}, {4, 5, 6}},
+		"s": []string{"x", "", "yz"},
+		"m": [][]string{{"a", "b"}, {"c", "d"}},
+	}
+	for name, want := range tests {
+		tensor, err := r.Tensor(name)
+		if err != nil {
+			t.Errorf("Tensor(%q): %v", name, err)
+			continue
+		}
+		if got := tensor.Value(); !reflect.DeepEqual(got, want) {
+			t.Errorf("Got %s = %v, want %v", name, got, want)
+		}
+	}
+	if _, err := r.Tensor("bad"); err == nil {
+		t.Errorf("Reading a tensor with a bad checksum did not fail")
+	}
+}
+
+func TestSliceKey(t *testing.T) {
+	slice := &pbs.TensorSliceProto{Extent: []*pbs.TensorSliceProto_Extent{
+		{Start: 64, HasLength: &pbs.TensorSliceProto_Extent_Length{Length: 2}},
+		{},
+	}}
+	want := "\x00" + "a\x00\xffb\x00\x01" + "\x01\x02" + "\xc0\x40" + "\x82" + "\x80" + "\x7f"
+	if got := sliceKey("a\x00b", slice); got != want {
+		t.Errorf("Got %q, want %q", got, want)
+	}
+	for v, want := range map[int64]string{
+		63: "\xbf", -64: "\x40", 8191: "\xdf\xff", -8193: "\x1f\xdf\xff",
+		math.MaxInt64: "\xff\xc0\x7f\xff\xff\xff\xff\xff\xff\xff",
+	} {
+		if got := string(appendSignedNumIncreasing(nil, v)); got != want {
+			t.Errorf("appendSignedNumIncreasing(%d) = %q, want %q", v, got, want)
+		}
+	}
+}
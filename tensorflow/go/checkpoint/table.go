@@ -0,0 +1,237 @@
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkpoint
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// The SSTable format of the .index files is the one of LevelDB, see
+// https://github.com/google/leveldb/blob/main/doc/table_format.md
+
+const (
+	tableMagic      = 0xdb4775248b80fb57
+	tableFooterSize = 2*blockHandleMaxSize + 8
+	// blockHandleMaxSize is the maximum size of two varint64 values.
+	blockHandleMaxSize = 2 * binary.MaxVarintLen64
+	// blockTrailerSize is the size of the compression type and checksum
+	// following each block.
+	blockTrailerSize = 5
+
+	noCompression     = 0
+	snappyCompression = 1
+)
+
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// maskDelta is added to rotated checksums to mask them, since computing
+// checksums of strings containing checksums is problematic.
+const maskDelta = 0xa282ead8
+
+func maskCRC(crc uint32) uint32 {
+	return (crc>>15 | crc<<17) + maskDelta
+}
+
+func unmaskCRC(masked uint32) uint32 {
+	rot := masked - maskDelta
+	return rot>>17 | rot<<15
+}
+
+type blockHandle struct {
+	offset, size uint64
+}
+
+func decodeBlockHandle(b []byte) (blockHandle, int, error) {
+	offset, n := binary.Uvarint(b)
+	if n <= 0 {
+		return blockHandle{}, 0, errors.New("bad block handle")
+	}
+	size, m := binary.Uvarint(b[n:])
+	if m <= 0 {
+		return blockHandle{}, 0, errors.New("bad block handle")
+	}
+	return blockHandle{offset, size}, n + m, nil
+}
+
+// readTable calls fn with the keys and values of the table in r in
+// ascending order of the keys.
+func readTable(r io.ReaderAt, size int64, fn func(key string, value []byte) error) error {
+	if size < tableFooterSize {
+		return errors.New("file is too short to be an sstable")
+	}
+	footer := make([]byte, tableFooterSize)
+	if _, err := r.ReadAt(footer, size-tableFooterSize); err != nil {
+		return err
+	}
+	if binary.LittleEndian.Uint64(footer[tableFooterSize-8:]) != tableMagic {
+		return errors.New("not an sstable (bad magic number)")
+	}
+	_, n, err := decodeBlockHandle(footer) // the meta index block is unused
+	if err != nil {
+		return err
+	}
+	indexHandle, _, err := decodeBlockHandle(footer[n:])
+	if err != nil {
+		return err
+	}
+	index, err := readBlock(r, indexHandle)
+	if err != nil {
+		return fmt.Errorf("failed to read index block: %w", err)
+	}
+	return iterateBlock(index, func(_ string, value []byte) error {
+		handle, _, err := decodeBlockHandle(value)
+		if err != nil {
+			return err
+		}
+		block, err := readBlock(r, handle)
+		if err != nil {
+			return fmt.Errorf("failed to read data block: %w", err)
+		}
+		return iterateBlock(block, fn)
+	})
+}
+
+// readBlock reads, verifies and decompresses the block.
+func readBlock(r io.ReaderAt, handle blockHandle) ([]byte, error) {
+	b := make([]byte, handle.size+blockTrailerSize)
+	if _, err := r.ReadAt(b, int64(handle.offset)); err != nil {
+		return nil, err
+	}
+	data, trailer := b[:handle.size], b[handle.size:]
+	crc := crc32.Update(crc32.Checksum(data, crc32cTable), crc32cTable, trailer[:1])
+	if unmaskCRC(binary.LittleEndian.Uint32(trailer[1:])) != crc {
+		return nil, errors.New("block checksum mismatch")
+	}
+	switch trailer[0] {
+	case noCompression:
+		return data, nil
+	case snappyCompression:
+		return snappyDecode(data)
+	}
+	return nil, fmt.Errorf("unsupported block compression type %d", trailer[0])
+}
+
+// iterateBlock calls fn for the entries of a block. The keys of the entries
+// share prefixes with the previous keys and the block ends with the offsets
+// of the entries which restart the prefix sharing.
+func iterateBlock(block []byte, fn func(key string, value []byte) error) error {
+	if len(block) < 4 {
+		return errors.New("block is too short")
+	}
+	numRestarts := binary.LittleEndian.Uint32(block[len(block)-4:])
+	if uint64(numRestarts+1)*4 > uint64(len(block)) {
+		return errors.New("bad number of restart points in block")
+	}
+	data := block[:len(block)-int(numRestarts+1)*4]
+	var key []byte
+	for len(data) > 0 {
+		var header [3]uint64
+		for i := range header {
+			v, n := binary.Uvarint(data)
+			if n <= 0 {
+				return errors.New("bad block entry")
+			}
+			header[i], data = v, data[n:]
+		}
+		shared, nonShared, valueLen := header[0], header[1], header[2]
+		if shared > uint64(len(key)) || nonShared+valueLen > uint64(len(data)) {
+			return errors.New("bad block entry")
+		}
+		key = append(key[:shared], data[:nonShared]...)
+		value := data[nonShared : nonShared+valueLen]
+		data = data[nonShared+valueLen:]
+		if err := fn(string(key), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// snappyDecode decodes a block compressed in the snappy format, see
+// https://github.com/google/snappy/blob/main/format_description.txt
+func snappyDecode(src []byte) ([]byte, error) {
+	errCorrupt := errors.New("corrupt snappy block")
+	dLen, n := binary.Uvarint(src)
+	if n <= 0 || dLen > 1<<32 {
+		return nil, errCorrupt
+	}
+	src = src[n:]
+	dst := make([]byte, 0, dLen)
+	for len(src) > 0 {
+		tag := src[0]
+		var length, offset int
+		switch tag & 3 {
+		case 0: // literal
+			length = int(tag >> 2)
+			src = src[1:]
+			if length >= 60 {
+				extra := length - 59
+				if len(src) < extra {
+					return nil, errCorrupt
+				}
+				length = 0
+				for i := extra - 1; i >= 0; i-- {
+					length = length<<8 | int(src[i])
+				}
+				src = src[extra:]
+			}
+			length++
+			if length > len(src) {
+				return nil, errCorrupt
+			}
+			dst = append(dst, src[:length]...)
+			src = src[length:]
+			continue
+		case 1:
+			if len(src) < 2 {
+				return nil, errCorrupt
+			}
+			length = 4 + int(tag>>2&7)
+			offset = int(tag&0xe0)<<3 | int(src[1])
+			src = src[2:]
+		case 2:
+			if len(src) < 3 {
+				return nil, errCorrupt
+			}
+			length = 1 + int(tag>>2)
+			offset = int(binary.LittleEndian.Uint16(src[1:]))
+			src = src[3:]
+		case 3:
+			if len(src) < 5 {
+				return nil, errCorrupt
+			}
+			length = 1 + int(tag>>2)
+			offset = int(binary.LittleEndian.Uint32(src[1:]))
+			src = src[5:]
+		}
+		if offset <= 0 || offset > len(dst) {
+			return nil, errCorrupt
+		}
+		// copy byte by byte since the copy may overlap its source
+		for end := len(dst) + length; len(dst) < end; {
+			dst = append(dst, dst[len(dst)-offset])
+		}
+	}
+	if uint64(len(dst)) != dLen {
+		return nil, errCorrupt
+	}
+	return dst, nil
+}
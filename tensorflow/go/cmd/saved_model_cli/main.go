@@ -0,0 +1,161 @@
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command saved_model_cli inspects and runs SavedModels without Python,
+// like the saved_model_cli command of the TensorFlow Python package.
+//
+// Usage:
+//
+//	saved_model_cli show -dir DIR [-tag_set TAGS [-signature_def KEY]] [-all] [-list_ops]
+//	saved_model_cli run -dir DIR -tag_set TAGS -signature_def KEY [-inputs INPUTS] [-input_exprs EXPRS] [-outdir DIR]
+//	saved_model_cli scan -dir DIR [-tag_set TAGS]
+//
+// The show command lists the tag-sets of the MetaGraphs, the signatures of
+// a MetaGraph, or the inputs and outputs of a signature with their data
+// types and shapes. With -list_ops it lists the operations used.
+//
+// The run command runs a signature and prints its outputs or saves them as
+// .npy files to the directory given by -outdir. The inputs are read from
+// .npy or .npz files with -inputs "key=file.npy;key2=file.npz[name]" or are
+// given as JSON literals with -input_exprs "key=[[1,2],[3,4]];key2=5",
+// which are converted to the data type of the input. Sparse inputs are fed
+// with the keys "key/indices", "key/values" and "key/dense_shape".
+//
+// The scan command reports MetaGraphs using operations on the denylist,
+// like ReadFile and WriteFile, which might be security risks.
+//
+// Tag-sets are given as comma-separated tags, like "serve,gpu".
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tf "github.com/hdu-hh/tensorflow/tensorflow/go"
+	"github.com/hdu-hh/tensorflow/tensorflow/go/pbs"
+	"google.golang.org/protobuf/proto"
+)
+
+// commands maps the names of the commands to their implementations.
+var commands = map[string]func(args []string, w io.Writer) error{
+	"show": show,
+	"run":  run,
+	"scan": scan,
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("saved_model_cli: ")
+	if len(os.Args) < 2 || commands[os.Args[1]] == nil {
+		fmt.Fprintln(os.Stderr, "usage: saved_model_cli show|run|scan [flags]")
+		fmt.Fprintln(os.Stderr, "Run 'saved_model_cli COMMAND -help' for the flags of a command.")
+		os.Exit(2)
+	}
+	if err := commands[os.Args[1]](os.Args[2:], os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// newFlagSet creates the flags of a command with the -dir and -tag_set flags.
+func newFlagSet(name string) (flags *flag.FlagSet, dir, tagSet *string) {
+	flags = flag.NewFlagSet(name, flag.ContinueOnError)
+	dir = flags.String("dir", "", "Directory containing the SavedModel.")
+	tagSet = flags.String("tag_set", "", "Comma-separated tags identifying the MetaGraph.")
+	return flags, dir, tagSet
+}
+
+// parseTags splits a comma-separated tag-set.
+func parseTags(tagSet string) []string {
+	var tags []string
+	for _, tag := range strings.Split(tagSet, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// sameTags reports whether the tags are the same regardless of their order.
+func sameTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// readMetaGraphs reads the MetaGraphs of the SavedModel in dir, which are
+// needed beyond the details returned by tf.ListSavedModelDetails to list the
+// operations.
+func readMetaGraphs(dir string) ([]*pbs.MetaGraphDef, error) {
+	b, err := os.ReadFile(filepath.Join(dir, "saved_model.pb"))
+	if err != nil {
+		return nil, err
+	}
+	var model pbs.SavedModel
+	if err := proto.Unmarshal(b, &model); err != nil {
+		return nil, fmt.Errorf("invalid SavedModel in %q: %w", dir, err)
+	}
+	return model.GetMetaGraphs(), nil
+}
+
+// usedOps returns the sorted types of the operations used by the MetaGraph,
+// including the operations of its functions.
+func usedOps(metaGraph *pbs.MetaGraphDef) []string {
+	seen := make(map[string]bool)
+	for _, node := range metaGraph.GetGraphDef().GetNode() {
+		seen[node.GetOp()] = true
+	}
+	for _, fn := range metaGraph.GetGraphDef().GetLibrary().GetFunction() {
+		for _, node := range fn.GetNodeDef() {
+			seen[node.GetOp()] = true
+		}
+	}
+	ops := make([]string, 0, len(seen))
+	for op := range seen {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+	return ops
+}
+
+// formatTags formats tags like the Python saved_model_cli.
+func formatTags(tags []string) string {
+	quoted := make([]string, len(tags))
+	for i, tag := range tags {
+		quoted[i] = "'" + tag + "'"
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// dataTypeName returns the name of the data type, like "DT_FLOAT".
+func dataTypeName(dt tf.DataType) string {
+	return pbs.DataType(dt).String()
+}
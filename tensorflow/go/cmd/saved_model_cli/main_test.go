@@ -0,0 +1,149 @@
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	tf "github.com/hdu-hh/tensorflow/tensorflow/go"
+	"github.com/hdu-hh/tensorflow/tensorflow/go/npy"
+)
+
+const savedModelSample = "../../testdata/saved_model/half_plus_two/00000123"
+
+func runCommand(t *testing.T, name string, args ...string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := commands[name](args, &buf); err != nil {
+		t.Fatalf("%s %v: %v", name, args, err)
+	}
+	return buf.String()
+}
+
+func TestShow(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"-dir", savedModelSample}, []string{"serve"}},
+		{[]string{"-dir", savedModelSample, "-tag_set", "serve"}, []string{`SignatureDef key: "regress_x2_to_y3"`, `SignatureDef key: "serving_default"`}},
+		{
+			[]string{"-dir", savedModelSample, "-tag_set", "serve", "-signature_def", "serving_default"},
+			[]string{"inputs['x'] tensor_info:", "dtype: DT_FLOAT", "name: x:0", "outputs['y'] tensor_info:", "Method name is: tensorflow/serving/predict"},
+		},
+		{[]string{"-dir", savedModelSample, "-all"}, []string{"signature_def['classify_x_to_y']:", "name: tf_example:0"}},
+		{[]string{"-dir", savedModelSample, "-tag_set", "serve", "-list_ops"}, []string{"ops: Add, Assign, Const,"}},
+	}
+	for _, test := range tests {
+		got := runCommand(t, "show", test.args...)
+		for _, want := range test.want {
+			if !strings.Contains(got, want) {
+				t.Errorf("show %v = %q, want it to contain %q", test.args, got, want)
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := show([]string{"-dir", savedModelSample, "-tag_set", "train"}, &buf); err == nil {
+		t.Errorf("show of a missing tag-set did not fail")
+	}
+}
+
+func TestScan(t *testing.T) {
+	got := runCommand(t, "scan", "-dir", savedModelSample)
+	if want := "MetaGraph with tag set ['serve'] does not contain the default denylisted ops"; !strings.HasPrefix(got, want) {
+		t.Errorf("Got %q, want prefix %q", got, want)
+	}
+}
+
+func TestRun(t *testing.T) {
+	args := []string{"-dir", savedModelSample, "-tag_set", "serve", "-input_exprs", "x=[[1],[3]]"}
+	if got, want := runCommand(t, "run", args...), "Result for output key y:\n[[2.5] [3.5]]\n"; got != want {
+		t.Errorf("Got %q, want %q", got, want)
+	}
+
+	dir := t.TempDir()
+	x, err := tf.NewTensor([][]float32{{2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := npy.WriteNpzFile(filepath.Join(dir, "x.npz"), map[string]*tf.Tensor{"a": x}); err != nil {
+		t.Fatal(err)
+	}
+	outdir := filepath.Join(dir, "out")
+	args = []string{"-dir", savedModelSample, "-tag_set", "serve", "-inputs", "x=" + filepath.Join(dir, "x.npz") + "[a]", "-outdir", outdir}
+	runCommand(t, "run", args...)
+	y, err := npy.ReadFile(filepath.Join(outdir, "y.npy"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := y.Value(), [][]float32{{3}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %v, want %v", got, want)
+	}
+	var buf bytes.Buffer
+	if err := run(args, &buf); err == nil {
+		t.Errorf("run without -overwrite did not fail for existing outputs")
+	}
+}
+
+func TestParseLiteral(t *testing.T) {
+	tests := []struct {
+		expr  string
+		dtype tf.DataType
+		want  interface{}
+	}{
+		{"5", tf.Int32, int32(5)},
+		{"[[1, 2], [3, 4]]", tf.Float, [][]float32{{1, 2}, {3, 4}}},
+		{"[-1, 2]", tf.Int64, []int64{-1, 2}},
+		{`["a", "b"]`, tf.String, []string{"a", "b"}},
+		{"[true]", tf.Bool, []bool{true}},
+		{"[]", tf.Double, []float64{}},
+	}
+	for _, test := range tests {
+		got, err := parseLiteral(test.expr, test.dtype)
+		if err != nil {
+			t.Errorf("parseLiteral(%q): %v", test.expr, err)
+			continue
+		}
+		if !reflect.DeepEqual(got.Value(), test.want) {
+			t.Errorf("parseLiteral(%q) = %v, want %v", test.expr, got.Value(), test.want)
+		}
+	}
+	for _, expr := range []string{"[[1], [2, 3]]", "[1.5]", `"a"`, "[1] [2]", "[300]"} {
+		if _, err := parseLiteral(expr, tf.Int8); err == nil {
+			t.Errorf("parseLiteral(%q) did not fail", expr)
+		}
+	}
+}
+
+func TestSplitAssignments(t *testing.T) {
+	got, err := splitAssignments("x=[1, 2]; y = a.npy[b];")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"x": "[1, 2]", "y": "a.npy[b]"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %v, want %v", got, want)
+	}
+	for _, s := range []string{"x", "=1", "x=1;x=2"} {
+		if _, err := splitAssignments(s); err == nil {
+			t.Errorf("splitAssignments(%q) did not fail", s)
+		}
+	}
+}
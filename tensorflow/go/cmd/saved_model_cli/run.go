@@ -0,0 +1,329 @@
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	tf "github.com/hdu-hh/tensorflow/tensorflow/go"
+	"github.com/hdu-hh/tensorflow/tensorflow/go/npy"
+)
+
+// run implements the run command.
+func run(args []string, w io.Writer) error {
+	flags, dir, tagSet := newFlagSet("run")
+	signatureKey := flags.String("signature_def", "serving_default", "Key of the signature to run.")
+	inputFiles := flags.String("inputs", "", `Inputs read from files as "key=file.npy;key2=file.npz[name]".`)
+	inputExprs := flags.String("input_exprs", "", `Inputs given as JSON literals as "key=[[1,2]];key2=\"a\"".`)
+	outdir := flags.String("outdir", "", "Directory to save the outputs to as .npy files instead of printing them.")
+	overwrite := flags.Bool("overwrite", false, "Overwrite existing files in the output directory.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *dir == "" || *tagSet == "" {
+		return fmt.Errorf("-dir and -tag_set must be set")
+	}
+	model, err := tf.LoadSavedModel(*dir, parseTags(*tagSet), nil)
+	if err != nil {
+		return err
+	}
+	defer model.Session.Close()
+	signature, ok := model.Signatures[*signatureKey]
+	if !ok {
+		return fmt.Errorf("SignatureDef %q not found", *signatureKey)
+	}
+
+	inputs := make(map[string]*tf.Tensor)
+	if err := readInputFiles(*inputFiles, inputs); err != nil {
+		return err
+	}
+	if err := parseInputExprs(*inputExprs, signature, inputs); err != nil {
+		return err
+	}
+	outputs, err := model.Signature(*signatureKey).Run(inputs)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range sortedKeys(outputs) {
+		if *outdir == "" {
+			fmt.Fprintf(w, "Result for output key %s:\n%v\n", key, outputs[key].Value())
+			continue
+		}
+		path := filepath.Join(*outdir, strings.ReplaceAll(key, "/", "_")+".npy")
+		if _, err := os.Stat(path); err == nil && !*overwrite {
+			return fmt.Errorf("output file %q already exists, use -overwrite to replace it", path)
+		}
+		if err := os.MkdirAll(*outdir, 0o755); err != nil {
+			return err
+		}
+		if err := npy.WriteFile(path, outputs[key]); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "Output %s is saved to %s\n", key, path)
+	}
+	return nil
+}
+
+// splitAssignments splits "key=value;key2=value2" into its assignments.
+func splitAssignments(s string) (map[string]string, error) {
+	assignments := make(map[string]string)
+	for _, assignment := range strings.Split(s, ";") {
+		if strings.TrimSpace(assignment) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(assignment, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid input %q, want key=value", assignment)
+		}
+		if _, ok := assignments[key]; ok {
+			return nil, fmt.Errorf("input %q is given more than once", key)
+		}
+		assignments[key] = strings.TrimSpace(value)
+	}
+	return assignments, nil
+}
+
+// readInputFiles reads the inputs given as "key=file.npy" or
+// "key=file.npz[name]". The first array of an .npz file is read if no
+// name is given.
+func readInputFiles(s string, inputs map[string]*tf.Tensor) error {
+	assignments, err := splitAssignments(s)
+	if err != nil {
+		return err
+	}
+	for key, file := range assignments {
+		name := ""
+		if i := strings.LastIndexByte(file, '['); i >= 0 && strings.HasSuffix(file, "]") {
+			file, name = file[:i], file[i+1:len(file)-1]
+		}
+		if !strings.HasSuffix(file, ".npz") {
+			if name != "" {
+				return fmt.Errorf("input %q: only .npz files contain named arrays", key)
+			}
+			if inputs[key], err = npy.ReadFile(file); err != nil {
+				return fmt.Errorf("input %q: %w", key, err)
+			}
+			continue
+		}
+		arrays, err := npy.ReadNpzFile(file)
+		if err != nil {
+			return fmt.Errorf("input %q: %w", key, err)
+		}
+		if name == "" {
+			keys := sortedKeys(arrays)
+			if len(keys) == 0 {
+				return fmt.Errorf("input %q: %q contains no arrays", key, file)
+			}
+			name = keys[0]
+		}
+		if inputs[key] = arrays[name]; inputs[key] == nil {
+			return fmt.Errorf("input %q: %q contains no array %q", key, file, name)
+		}
+	}
+	return nil
+}
+
+// parseInputExprs parses the inputs given as "key=literal", which are
+// converted to the data types of the inputs of the signature.
+func parseInputExprs(s string, signature tf.Signature, inputs map[string]*tf.Tensor) error {
+	assignments, err := splitAssignments(s)
+	if err != nil {
+		return err
+	}
+	for key, expr := range assignments {
+		if _, ok := inputs[key]; ok {
+			return fmt.Errorf("input %q is given more than once", key)
+		}
+		dtype, ok := inputDataType(signature, key)
+		if !ok {
+			return fmt.Errorf("signature has no input %q", key)
+		}
+		if inputs[key], err = parseLiteral(expr, dtype); err != nil {
+			return fmt.Errorf("input %q: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// inputDataType returns the data type of the input with the key, which may
+// also be a key of a part of a sparse or composite input (see
+// [tf.SignatureRunner]).
+func inputDataType(signature tf.Signature, key string) (tf.DataType, bool) {
+	if info, ok := signature.Inputs[key]; ok {
+		return info.DType, info.CooSparse == nil && info.Components == nil
+	}
+	i := strings.LastIndexByte(key, '/')
+	if i < 0 {
+		return 0, false
+	}
+	parent, part := key[:i], key[i+1:]
+	if info, ok := signature.Inputs[parent]; ok && info.CooSparse != nil {
+		switch part {
+		case "indices", "dense_shape":
+			return tf.Int64, true
+		case "values":
+			return info.DType, true
+		}
+		return 0, false
+	}
+	info, ok := signature.Inputs[parent]
+	if !ok {
+		return 0, false
+	}
+	index, err := strconv.Atoi(part)
+	if err != nil || index < 0 || index >= len(info.Components) {
+		return 0, false
+	}
+	component := info.Components[index]
+	return component.DType, component.CooSparse == nil && component.Components == nil
+}
+
+// literalTypes maps the data types to the Go types of the elements of
+// literals.
+var literalTypes = map[tf.DataType]reflect.Type{
+	tf.Float:  reflect.TypeOf(float32(0)),
+	tf.Double: reflect.TypeOf(float64(0)),
+	tf.Int8:   reflect.TypeOf(int8(0)),
+	tf.Int16:  reflect.TypeOf(int16(0)),
+	tf.Int32:  reflect.TypeOf(int32(0)),
+	tf.Int64:  reflect.TypeOf(int64(0)),
+	tf.Uint8:  reflect.TypeOf(uint8(0)),
+	tf.Uint16: reflect.TypeOf(uint16(0)),
+	tf.Uint32: reflect.TypeOf(uint32(0)),
+	tf.Uint64: reflect.TypeOf(uint64(0)),
+	tf.Bool:   reflect.TypeOf(false),
+	tf.String: reflect.TypeOf(""),
+}
+
+// parseLiteral parses a JSON literal, a scalar or nested arrays of the same
+// length, into a tensor of the data type.
+func parseLiteral(expr string, dtype tf.DataType) (*tf.Tensor, error) {
+	elemType, ok := literalTypes[dtype]
+	if !ok {
+		return nil, fmt.Errorf("literals of data type %s are not supported", dataTypeName(dtype))
+	}
+	dec := json.NewDecoder(bytes.NewReader([]byte(expr)))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid literal %q: %w", expr, err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("invalid literal %q: trailing data", expr)
+	}
+
+	var shape []int64
+	for v := value; ; {
+		list, ok := v.([]interface{})
+		if !ok {
+			break
+		}
+		shape = append(shape, int64(len(list)))
+		if len(list) == 0 {
+			break
+		}
+		v = list[0]
+	}
+	var elems []interface{}
+	var flatten func(v interface{}, dim int) error
+	flatten = func(v interface{}, dim int) error {
+		if dim == len(shape) {
+			elems = append(elems, v)
+			return nil
+		}
+		list, ok := v.([]interface{})
+		if !ok || int64(len(list)) != shape[dim] {
+			return fmt.Errorf("invalid literal %q: arrays of dimension %d differ in length", expr, dim)
+		}
+		for _, x := range list {
+			if err := flatten(x, dim+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := flatten(value, 0); err != nil {
+		return nil, err
+	}
+
+	flat := reflect.MakeSlice(reflect.SliceOf(elemType), len(elems), len(elems))
+	for i, elem := range elems {
+		if err := setLiteralElem(flat.Index(i), elem); err != nil {
+			return nil, fmt.Errorf("invalid literal %q: %w", expr, err)
+		}
+	}
+	if shape == nil {
+		return tf.NewTensor(flat.Index(0).Interface())
+	}
+	t, err := tf.NewTensor(flat.Interface())
+	if err != nil {
+		return nil, err
+	}
+	return t, t.Reshape(shape)
+}
+
+// setLiteralElem sets v to an element of a JSON literal.
+func setLiteralElem(v reflect.Value, elem interface{}) error {
+	var err error
+	switch v.Kind() {
+	case reflect.Bool:
+		b, ok := elem.(bool)
+		if !ok {
+			return fmt.Errorf("got %v, want a boolean", elem)
+		}
+		v.SetBool(b)
+	case reflect.String:
+		s, ok := elem.(string)
+		if !ok {
+			return fmt.Errorf("got %v, want a string", elem)
+		}
+		v.SetString(s)
+	default:
+		n, ok := elem.(json.Number)
+		if !ok {
+			return fmt.Errorf("got %v, want a number", elem)
+		}
+		switch v.Kind() {
+		case reflect.Float32, reflect.Float64:
+			var f float64
+			if f, err = strconv.ParseFloat(n.String(), v.Type().Bits()); err == nil {
+				v.SetFloat(f)
+			}
+		case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			var i int64
+			if i, err = strconv.ParseInt(n.String(), 10, v.Type().Bits()); err == nil {
+				v.SetInt(i)
+			}
+		default:
+			var u uint64
+			if u, err = strconv.ParseUint(n.String(), 10, v.Type().Bits()); err == nil {
+				v.SetUint(u)
+			}
+		}
+	}
+	return err
+}
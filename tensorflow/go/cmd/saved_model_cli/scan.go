@@ -0,0 +1,69 @@
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// denylistedOps are the operations reported by the scan command, since they
+// access the file system or output data of the host.
+var denylistedOps = map[string]bool{
+	"ReadFile":  true,
+	"WriteFile": true,
+	"PrintV2":   true,
+}
+
+// scan implements the scan command.
+func scan(args []string, w io.Writer) error {
+	flags, dir, tagSet := newFlagSet("scan")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *dir == "" {
+		return fmt.Errorf("-dir must be set")
+	}
+	metaGraphs, err := readMetaGraphs(*dir)
+	if err != nil {
+		return err
+	}
+	wanted := parseTags(*tagSet)
+	found := false
+	for _, metaGraph := range metaGraphs {
+		tags := metaGraph.GetMetaInfoDef().GetTags()
+		if len(wanted) > 0 && !sameTags(tags, wanted) {
+			continue
+		}
+		found = true
+		var denylisted []string
+		for _, op := range usedOps(metaGraph) {
+			if denylistedOps[op] {
+				denylisted = append(denylisted, op)
+			}
+		}
+		if len(denylisted) == 0 {
+			fmt.Fprintf(w, "MetaGraph with tag set %s does not contain the default denylisted ops: %v\n", formatTags(tags), sortedKeys(denylistedOps))
+		} else {
+			fmt.Fprintf(w, "MetaGraph with tag set %s contains the following denylisted ops: %v\n", formatTags(tags), denylisted)
+		}
+	}
+	if !found {
+		return fmt.Errorf("no MetaGraphDef with tag-set %s found in %q", formatTags(wanted), *dir)
+	}
+	return nil
+}
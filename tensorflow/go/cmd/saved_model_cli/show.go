@@ -0,0 +1,159 @@
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	tf "github.com/hdu-hh/tensorflow/tensorflow/go"
+)
+
+// show implements the show command.
+func show(args []string, w io.Writer) error {
+	flags, dir, tagSet := newFlagSet("show")
+	signatureKey := flags.String("signature_def", "", "Key of the signature to show the inputs and outputs of.")
+	all := flags.Bool("all", false, "Show all MetaGraphs with all their signatures.")
+	listOps := flags.Bool("list_ops", false, "List the operations used by the MetaGraphs.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *dir == "" {
+		return fmt.Errorf("-dir must be set")
+	}
+	tagSets, signatures, err := tf.ListSavedModelDetails(*dir)
+	if err != nil {
+		return err
+	}
+	if len(tagSets) == 0 {
+		return fmt.Errorf("no SavedModel found in %q", *dir)
+	}
+	var ops [][]string
+	if *listOps {
+		metaGraphs, err := readMetaGraphs(*dir)
+		if err != nil {
+			return err
+		}
+		for _, metaGraph := range metaGraphs {
+			ops = append(ops, usedOps(metaGraph))
+		}
+	}
+
+	if *all {
+		for i, tags := range tagSets {
+			fmt.Fprintf(w, "\nMetaGraphDef with tag-set: %s contains the following SignatureDefs:\n", formatTags(tags))
+			for _, key := range sortedKeys(signatures[i]) {
+				fmt.Fprintf(w, "\nsignature_def['%s']:\n", key)
+				showSignature(w, signatures[i][key])
+			}
+			if ops != nil {
+				showOps(w, tags, ops[i])
+			}
+		}
+		return nil
+	}
+	if *tagSet == "" {
+		fmt.Fprintln(w, "The given SavedModel contains the following tag-sets:")
+		for i, tags := range tagSets {
+			fmt.Fprintln(w, strings.Join(tags, ", "))
+			if ops != nil {
+				showOps(w, tags, ops[i])
+			}
+		}
+		return nil
+	}
+
+	wanted := parseTags(*tagSet)
+	for i, tags := range tagSets {
+		if !sameTags(tags, wanted) {
+			continue
+		}
+		if *signatureKey == "" {
+			fmt.Fprintln(w, "The given SavedModel MetaGraphDef contains SignatureDefs with the following keys:")
+			for _, key := range sortedKeys(signatures[i]) {
+				fmt.Fprintf(w, "SignatureDef key: %q\n", key)
+			}
+		} else {
+			signature, ok := signatures[i][*signatureKey]
+			if !ok {
+				return fmt.Errorf("MetaGraphDef with tag-set %s has no SignatureDef %q", formatTags(tags), *signatureKey)
+			}
+			showSignature(w, signature)
+		}
+		if ops != nil {
+			showOps(w, tags, ops[i])
+		}
+		return nil
+	}
+	return fmt.Errorf("no MetaGraphDef with tag-set %s found in %q", formatTags(wanted), *dir)
+}
+
+// showSignature shows the inputs, outputs and method name of a signature.
+func showSignature(w io.Writer, signature tf.Signature) {
+	fmt.Fprintln(w, "The given SavedModel SignatureDef contains the following input(s):")
+	for _, key := range sortedKeys(signature.Inputs) {
+		fmt.Fprintf(w, "  inputs['%s'] tensor_info:\n", key)
+		showTensorInfo(w, signature.Inputs[key], "      ")
+	}
+	fmt.Fprintln(w, "The given SavedModel SignatureDef contains the following output(s):")
+	for _, key := range sortedKeys(signature.Outputs) {
+		fmt.Fprintf(w, "  outputs['%s'] tensor_info:\n", key)
+		showTensorInfo(w, signature.Outputs[key], "      ")
+	}
+	fmt.Fprintf(w, "Method name is: %s\n", signature.MethodName)
+}
+
+// showTensorInfo shows the data type, shape and tensor names of an input or
+// output with the given indentation.
+func showTensorInfo(w io.Writer, info tf.TensorInfo, indent string) {
+	switch {
+	case info.CooSparse != nil:
+		fmt.Fprintf(w, "%sdtype: %s\n", indent, dataTypeName(info.DType))
+		fmt.Fprintf(w, "%sshape: %v\n", indent, info.Shape)
+		fmt.Fprintf(w, "%sCOO sparse tensor:\n", indent)
+		fmt.Fprintf(w, "%s  indices: %s\n", indent, info.CooSparse.IndicesName)
+		fmt.Fprintf(w, "%s  values: %s\n", indent, info.CooSparse.ValuesName)
+		fmt.Fprintf(w, "%s  dense_shape: %s\n", indent, info.CooSparse.DenseShapeName)
+	case info.Components != nil:
+		fmt.Fprintf(w, "%scomposite tensor with %d component(s):\n", indent, len(info.Components))
+		for i, component := range info.Components {
+			fmt.Fprintf(w, "%s  component %d:\n", indent, i)
+			showTensorInfo(w, component, indent+"    ")
+		}
+	default:
+		fmt.Fprintf(w, "%sdtype: %s\n", indent, dataTypeName(info.DType))
+		fmt.Fprintf(w, "%sshape: %v\n", indent, info.Shape)
+		fmt.Fprintf(w, "%sname: %s\n", indent, info.Name)
+	}
+}
+
+// showOps lists the operations used by a MetaGraph.
+func showOps(w io.Writer, tags []string, ops []string) {
+	fmt.Fprintf(w, "The MetaGraph with tag set %s contains the following ops: %s\n", formatTags(tags), strings.Join(ops, ", "))
+}
+
+// sortedKeys returns the keys of the map in ascending order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -19,12 +19,49 @@ package tensorflow
 // #include <stdlib.h>
 // #include "tensorflow/c/c_api.h"
 // #include "tensorflow/c/eager/c_api.h"
+// #include "tensorflow/go/c_api_experimental.h"
 import "C"
 import (
 	"fmt"
 	"runtime"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/hdu-hh/tensorflow/tensorflow/go/pbs"
+	"google.golang.org/protobuf/proto"
+)
+
+// DevicePlacementPolicy controls how to execute an operation whose input
+// tensors are not on the device of the operation.
+type DevicePlacementPolicy int
+
+const (
+	// DevicePlacementSilent silently copies the input tensors to the device,
+	// which blocks the operation until the copies are complete.
+	// It is the default policy.
+	DevicePlacementSilent DevicePlacementPolicy = iota
+	// DevicePlacementWarn copies the input tensors but logs a warning.
+	DevicePlacementWarn
+	// DevicePlacementExplicit fails the operation.
+	DevicePlacementExplicit
+	// DevicePlacementSilentForInt32 silently copies int32 tensors but fails
+	// for other tensors.
+	DevicePlacementSilentForInt32
 )
 
+func (p DevicePlacementPolicy) c() C.TFE_ContextDevicePlacementPolicy {
+	switch p {
+	case DevicePlacementWarn:
+		return C.TFE_DEVICE_PLACEMENT_WARN
+	case DevicePlacementExplicit:
+		return C.TFE_DEVICE_PLACEMENT_EXPLICIT
+	case DevicePlacementSilentForInt32:
+		return C.TFE_DEVICE_PLACEMENT_SILENT_FOR_INT32
+	}
+	return C.TFE_DEVICE_PLACEMENT_SILENT
+}
+
 // ContextOptions contains configuration information for a session
 type ContextOptions struct {
 	// Config is a binary-serialized representation of the
@@ -34,6 +71,10 @@ type ContextOptions struct {
 
 	// Sets the default execution mode
 	Async bool
+
+	// DevicePlacementPolicy controls the execution of operations whose
+	// inputs are on other devices.
+	DevicePlacementPolicy DevicePlacementPolicy
 }
 
 // c converts the ContextOptions to the C API's TF_ContextOptions.
@@ -60,6 +101,7 @@ func (o *ContextOptions) c() (*C.TFE_ContextOptions, error) {
 		async = 1
 	}
 	C.TFE_ContextOptionsSetAsync(opt, C.uchar(async))
+	C.TFE_ContextOptionsSetDevicePlacementPolicy(opt, o.DevicePlacementPolicy.c())
 
 	return opt, nil
 }
@@ -72,6 +114,10 @@ func (o *ContextOptions) c() (*C.TFE_ContextOptions, error) {
 // configure a Session when executing a Graph.
 type Context struct {
 	c *C.TFE_Context
+
+	// tapes are the gradient tapes recording the executed operations.
+	mu    sync.Mutex
+	tapes []*GradientTape
 }
 
 // NewContext creates a new context for eager execution.
@@ -101,9 +147,102 @@ func (c *Context) finalizer() {
 func (c *Context) ListDevices() ([]Device, error) {
 	status := newStatus()
 	devicesList := C.TFE_ContextListDevices(c.c, status.c)
+	runtime.KeepAlive(c)
 	if err := status.Err(); err != nil {
 		return nil, fmt.Errorf("SessionListDevices() failed: %w", err)
 	}
 	defer C.TF_DeleteDeviceList(devicesList)
 	return deviceSliceFromDeviceList(devicesList)
 }
+
+// SetServerDef makes the remote workers specified by the
+// [pbs.ServerDef] available to the Context. Operations can then be
+// executed on them by setting their device, like
+// "/job:worker/replica:0/task:1/device:CPU:0". The ServerDef describes the
+// task of the Context itself, whose devices become the local devices.
+// The remote contexts are kept alive for keepAlive after their last use.
+func (c *Context) SetServerDef(def *pbs.ServerDef, keepAlive time.Duration) error {
+	buf, err := proto.Marshal(def)
+	if err != nil {
+		return fmt.Errorf("invalid ServerDef: %w", err)
+	}
+	if len(buf) == 0 {
+		buf = []byte{0}
+	}
+	status := newStatus()
+	C.TFE_ContextSetServerDef(c.c, C.int(keepAlive/time.Second), unsafe.Pointer(&buf[0]), C.size_t(len(buf)), status.c)
+	runtime.KeepAlive(c)
+	if err := status.Err(); err != nil {
+		return fmt.Errorf("SetServerDef() failed: %w", err)
+	}
+	return nil
+}
+
+// AddFunction copies the function into the Context, such that it can be
+// executed by [Context.Execute] with its name as the operation type.
+func (c *Context) AddFunction(fn *Func) error {
+	if fn == nil {
+		return fmt.Errorf("cannot add a nil Func")
+	}
+	status := newStatus()
+	C.TFE_ContextAddFunction(c.c, fn.c, status.c)
+	runtime.KeepAlive(c)
+	if err := status.Err(); err != nil {
+		return fmt.Errorf("AddFunction() failed: %w", err)
+	}
+	return nil
+}
+
+// EnableRunMetadata enables collecting the [pbs.RunMetadata] of the
+// operations executed in the Context.
+func (c *Context) EnableRunMetadata() {
+	C.TFE_ContextEnableRunMetadata(c.c)
+	runtime.KeepAlive(c)
+}
+
+// DisableRunMetadata disables collecting the [pbs.RunMetadata].
+func (c *Context) DisableRunMetadata() {
+	C.TFE_ContextDisableRunMetadata(c.c)
+	runtime.KeepAlive(c)
+}
+
+// ExportRunMetadata returns the [pbs.RunMetadata] collected since enabling
+// it or the previous export and clears it. In async mode it waits for
+// the pending operations.
+func (c *Context) ExportRunMetadata() (*pbs.RunMetadata, error) {
+	buf := C.TF_NewBuffer()
+	defer C.TF_DeleteBuffer(buf)
+	status := newStatus()
+	C.TFE_ContextExportRunMetadata(c.c, buf, status.c)
+	runtime.KeepAlive(c)
+	if err := status.Err(); err != nil {
+		return nil, fmt.Errorf("ExportRunMetadata() failed: %w", err)
+	}
+	metadata := new(pbs.RunMetadata)
+	if buf.length > 0 {
+		b, err := getBufferAsSlice(buf)
+		if err != nil {
+			return nil, err
+		}
+		if err := proto.Unmarshal(b, metadata); err != nil {
+			return nil, fmt.Errorf("invalid RunMetadata: %w", err)
+		}
+	}
+	return metadata, nil
+}
+
+// WaitForPending waits until the operations executed asynchronously have
+// completed and returns their first error, if any.
+func (c *Context) WaitForPending() error {
+	status := newStatus()
+	C.TFE_ContextAsyncWait(c.c, status.c)
+	runtime.KeepAlive(c)
+	return status.Err()
+}
+
+// ClearCaches clears the internal caches of the Context, e.g. the kernels
+// of random operations, which is needed to reseed them.
+func (c *Context) ClearCaches() {
+	C.TFE_ContextClearCaches(c.c)
+	runtime.KeepAlive(c)
+}
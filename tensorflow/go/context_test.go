@@ -18,7 +18,13 @@ package tensorflow
 
 import (
 	"fmt"
+	"math"
+	"net"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/hdu-hh/tensorflow/tensorflow/go/pbs"
 )
 
 func TestContextConfigSetAsync(t *testing.T) {
@@ -55,3 +61,170 @@ func TestContextConfigListDevices(t *testing.T) {
 		t.Error("Failed to find CPU device using ListDevices()")
 	}
 }
+
+func TestContextDevicePlacementPolicy(t *testing.T) {
+	// a second CPU device holds the input of operations on the first one
+	config := pbs.MustMarshal(&pbs.ConfigProto{DeviceCount: map[string]int32{"CPU": 2}})
+	tests := []struct {
+		policy  DevicePlacementPolicy
+		wantErr bool
+	}{
+		{DevicePlacementSilent, false},
+		{DevicePlacementWarn, false},
+		{DevicePlacementExplicit, true},
+		{DevicePlacementSilentForInt32, true}, // float inputs are not copied
+	}
+	for _, test := range tests {
+		ctx, err := NewContext(&ContextOptions{Config: config, DevicePlacementPolicy: test.policy})
+		if err != nil {
+			t.Fatal(err)
+		}
+		devices, err := ctx.ListDevices()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var cpus []string
+		for _, d := range devices {
+			if d.Type == "CPU" {
+				cpus = append(cpus, d.Name)
+			}
+		}
+		if len(cpus) < 2 {
+			t.Fatalf("Got CPU devices %v, want 2", cpus)
+		}
+		x, err := mustTensorHandle(t, []float32{1}).CopyToDevice(ctx, cpus[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		outputs, err := ctx.Execute(EagerOpSpec{Type: "Neg", Device: cpus[0]}, x)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("policy %d: executing with an input on another device did not fail", test.policy)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("policy %d: %v", test.policy, err)
+		}
+		checkHandleValue(t, outputs[0], []float32{-1})
+	}
+}
+
+func TestContextAddFunction(t *testing.T) {
+	ctx, err := NewContext(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fn := getNegFunc(t, "ctxNeg")
+	if _, err := ctx.Execute(EagerOpSpec{Type: fn.Name(), NumOutputs: 1}, mustTensorHandle(t, int8(3))); err == nil {
+		t.Errorf("Executing an unknown function did not fail")
+	}
+	if err := ctx.AddFunction(fn); err != nil {
+		t.Fatal(err)
+	}
+	outputs, err := ctx.Execute(EagerOpSpec{Type: fn.Name(), NumOutputs: 1}, mustTensorHandle(t, int8(3)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkHandleValue(t, outputs[0], int8(-3))
+	if err := ctx.AddFunction(nil); err == nil {
+		t.Errorf("AddFunction(nil) did not fail")
+	}
+}
+
+func TestContextRunMetadata(t *testing.T) {
+	ctx, err := NewContext(&ContextOptions{Async: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx.EnableRunMetadata()
+	if _, err := ctx.Execute(EagerOpSpec{Type: "Neg"}, mustTensorHandle(t, []float32{1, 2})); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.WaitForPending(); err != nil {
+		t.Fatal(err)
+	}
+	metadata, err := ctx.ExportRunMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var nodes []string
+	for _, dev := range metadata.GetStepStats().GetDevStats() {
+		for _, node := range dev.GetNodeStats() {
+			nodes = append(nodes, node.GetNodeName())
+		}
+	}
+	if !strings.Contains(strings.Join(nodes, " "), "Neg") {
+		t.Errorf("Got step stats of the nodes %v, want those of Neg", nodes)
+	}
+	ctx.DisableRunMetadata()
+	ctx.ClearCaches()
+
+	// errors of asynchronous operations are reported by WaitForPending
+	_, err = ctx.Execute(EagerOpSpec{
+		Type:  "CheckNumerics",
+		Attrs: map[string]interface{}{"message": "not finite"},
+	}, mustTensorHandle(t, []float32{float32(math.Inf(1))}))
+	if err == nil {
+		err = ctx.WaitForPending()
+	}
+	if err == nil {
+		t.Errorf("Failing asynchronous operation did not report an error")
+	}
+}
+
+// unusedAddress returns a local address which is currently not in use.
+func unusedAddress(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
+
+func TestContextSetServerDef(t *testing.T) {
+	cluster := &pbs.ClusterDef{Job: []*pbs.JobDef{
+		{Name: "localhost", Tasks: map[int32]string{0: unusedAddress(t)}},
+		{Name: "worker", Tasks: map[int32]string{0: unusedAddress(t)}},
+	}}
+	server, err := NewServer(&pbs.ServerDef{Cluster: cluster, JobName: "worker", Protocol: "grpc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := server.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+	if server.Target() == "" {
+		t.Errorf("Server has no target")
+	}
+
+	ctx, err := NewContext(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.SetServerDef(&pbs.ServerDef{Cluster: cluster, JobName: "localhost", Protocol: "grpc"}, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	outputs, err := ctx.Execute(EagerOpSpec{
+		Type:   "Neg",
+		Device: "/job:worker/replica:0/task:0/device:CPU:0",
+	}, mustTensorHandle(t, []int64{1, 2}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	device, err := outputs[0].DeviceName()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "/job:worker/replica:0/task:0/device:CPU:0"; device != want {
+		t.Errorf("Got device %q, want %q", device, want)
+	}
+	local, err := outputs[0].CopyToDevice(ctx, "/job:localhost/replica:0/task:0/device:CPU:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkHandleValue(t, local, []int64{-1, -2})
+}
@@ -0,0 +1,299 @@
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tensorflow
+
+// #include <stdlib.h>
+// #include "tensorflow/c/c_api.h"
+// #include "tensorflow/c/eager/c_api.h"
+// #include "tensorflow/go/c_api_experimental.h"
+//
+// void TFE_OpSetAttrShapeList_Helper(TFE_Op* op,
+//                                    const char* attr_name,
+//                                    const int64_t* flat_dims,
+//                                    const int* num_dims,
+//                                    int num_shapes,
+//                                    TF_Status* status) {
+//  const int64_t** dims =
+//    (const int64_t**)malloc(sizeof(const int64_t*) * num_shapes);
+//  int i = 0;
+//  for (i = 0; i < num_shapes; i++) {
+//    dims[i] = flat_dims;
+//    if (num_dims[i] > 0) {
+//      flat_dims += num_dims[i];
+//    }
+//  }
+//  TFE_OpSetAttrShapeList(op, attr_name, dims, num_dims, num_shapes, status);
+//  free(dims);
+// }
+import "C"
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"unsafe"
+
+	"github.com/hdu-hh/tensorflow/tensorflow/go/pbs"
+)
+
+// EagerOpSpec is the specification of an operation to be executed eagerly
+// (using [Context.Execute]).
+type EagerOpSpec struct {
+	// Type of the operation (e.g., "Add", "MatMul") or the name of a
+	// function registered in the Context.
+	Type string
+
+	// Map from attribute name to its value. The attributes which can be
+	// inferred from the inputs, like the type "T" of "Add", may be omitted.
+	Attrs map[string]interface{}
+
+	// The device on which the operation should be executed.
+	// If omitted, an appropriate device will automatically be selected.
+	Device string
+
+	// NumOutputs is the number of outputs of the operation. If omitted, it is
+	// derived from the registered definition of the operation. Functions are
+	// assumed to have at most defaultNumEagerOutputs outputs.
+	NumOutputs int
+}
+
+// defaultNumEagerOutputs is the number of outputs reserved for operations
+// whose number of outputs cannot be derived.
+const defaultNumEagerOutputs = 16
+
+// EagerInput is the interface for the inputs of eagerly executed operations,
+// either a [*TensorHandle] or a [TensorHandleList].
+type EagerInput interface {
+	// Unexported to preclude implementations outside this package.
+	canBeAnEagerInput()
+}
+
+func (th *TensorHandle) canBeAnEagerInput() {}
+
+// TensorHandleList is a list of tensor handles passed as a single input which
+// expects a list of tensors, like the "values" input of "ConcatV2".
+type TensorHandleList []*TensorHandle
+
+func (l TensorHandleList) canBeAnEagerInput() {}
+
+// Execute executes the operation immediately and returns handles to its
+// outputs. The inputs are passed in the order of the inputs of the operation.
+//
+// If the Context executes asynchronously, Execute may return before the
+// operation has been executed and errors are reported by later calls.
+//
+// The operation is recorded by the active gradient tapes of the Context
+// if it depends on the tensors watched by them (see [GradientTape]).
+func (c *Context) Execute(spec EagerOpSpec, inputs ...EagerInput) ([]*TensorHandle, error) {
+	outputs, err := c.execute(spec, inputs)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	tapes := c.tapes
+	c.mu.Unlock()
+	for _, tape := range tapes {
+		tape.record(spec, inputs, outputs)
+	}
+	return outputs, nil
+}
+
+// execute executes the operation without recording it.
+func (c *Context) execute(spec EagerOpSpec, inputs []EagerInput) ([]*TensorHandle, error) {
+	status := newStatus()
+	cType := C.CString(spec.Type)
+	op := C.TFE_NewOp(c.c, cType, status.c)
+	C.free(unsafe.Pointer(cType))
+	if err := status.Err(); err != nil {
+		return nil, fmt.Errorf("failed to create operation %q: %w", spec.Type, err)
+	}
+	defer C.TFE_DeleteOp(op)
+
+	if spec.Device != "" {
+		cDevice := C.CString(spec.Device)
+		C.TFE_OpSetDevice(op, cDevice, status.c)
+		C.free(unsafe.Pointer(cDevice))
+		if err := status.Err(); err != nil {
+			return nil, fmt.Errorf("failed to set device of operation %q: %w", spec.Type, err)
+		}
+	}
+	for i, input := range inputs {
+		switch input := input.(type) {
+		case *TensorHandle:
+			C.TFE_OpAddInput(op, input.c, status.c)
+		case TensorHandleList:
+			list := make([]*C.TFE_TensorHandle, len(input))
+			for j, th := range input {
+				list[j] = th.c
+			}
+			var plist **C.TFE_TensorHandle
+			if len(list) > 0 {
+				plist = &list[0]
+			}
+			C.TFE_OpAddInputList(op, plist, C.int(len(list)), status.c)
+		}
+		if err := status.Err(); err != nil {
+			return nil, fmt.Errorf("failed to add input %d to operation %q: %w", i, spec.Type, err)
+		}
+	}
+	for name, value := range spec.Attrs {
+		if err := setAttr(eagerOp{op}, status, name, value); err != nil {
+			return nil, fmt.Errorf("failed to set attributes of operation %q: %w", spec.Type, err)
+		}
+	}
+
+	numOutputs := spec.NumOutputs
+	if numOutputs <= 0 {
+		numOutputs = eagerNumOutputs(op, spec.Type)
+	}
+	retvals := make([]*C.TFE_TensorHandle, numOutputs+1)
+	cNumOutputs := C.int(numOutputs)
+	C.TFE_Execute(op, &retvals[0], &cNumOutputs, status.c)
+	runtime.KeepAlive(inputs)
+	runtime.KeepAlive(c)
+	if err := status.Err(); err != nil {
+		return nil, fmt.Errorf("failed to execute operation %q: %w", spec.Type, err)
+	}
+	outputs := make([]*TensorHandle, int(cNumOutputs))
+	for i := range outputs {
+		outputs[i] = newTensorHandleFromC(retvals[i])
+	}
+	return outputs, nil
+}
+
+// outputArgs holds the names of the outputs of all registered operations.
+var outputArgs struct {
+	once  sync.Once
+	names map[string][]string
+}
+
+// eagerNumOutputs returns the number of outputs of the eager operation op of
+// the given type, whose inputs and attributes have been set.
+func eagerNumOutputs(op *C.TFE_Op, opType string) int {
+	outputArgs.once.Do(func() {
+		outputArgs.names = make(map[string][]string)
+		for _, def := range GetAllOpList().GetOp() {
+			names := make([]string, len(def.GetOutputArg()))
+			for i, arg := range def.GetOutputArg() {
+				names[i] = arg.GetName()
+			}
+			outputArgs.names[def.GetName()] = names
+		}
+	})
+	names, ok := outputArgs.names[opType]
+	if !ok {
+		return defaultNumEagerOutputs
+	}
+	status := newStatus()
+	n := 0
+	for _, name := range names {
+		cName := C.CString(name)
+		n += int(C.TFE_OpGetOutputLength(op, cName, status.c))
+		C.free(unsafe.Pointer(cName))
+		if status.Err() != nil {
+			return defaultNumEagerOutputs
+		}
+	}
+	return n
+}
+
+// eagerOp sets the attributes of an eager operation.
+type eagerOp struct {
+	c *C.TFE_Op
+}
+
+func (op eagerOp) setString(name *C.char, value unsafe.Pointer, length C.size_t) {
+	C.TFE_OpSetAttrString(op.c, name, value, length)
+}
+
+func (op eagerOp) setStringList(name *C.char, values *unsafe.Pointer, lengths *C.size_t, n C.int) {
+	C.TFE_OpSetAttrStringList(op.c, name, values, lengths, n)
+}
+
+func (op eagerOp) setInt(name *C.char, value C.int64_t) {
+	C.TFE_OpSetAttrInt(op.c, name, value)
+}
+
+func (op eagerOp) setIntList(name *C.char, values *C.int64_t, n C.int) {
+	C.TFE_OpSetAttrIntList(op.c, name, values, n)
+}
+
+func (op eagerOp) setFloat(name *C.char, value C.float) {
+	C.TFE_OpSetAttrFloat(op.c, name, value)
+}
+
+func (op eagerOp) setFloatList(name *C.char, values *C.float, n C.int) {
+	C.TFE_OpSetAttrFloatList(op.c, name, values, n)
+}
+
+func (op eagerOp) setBool(name *C.char, value C.uchar) {
+	C.TFE_OpSetAttrBool(op.c, name, value)
+}
+
+func (op eagerOp) setBoolList(name *C.char, values *C.uchar, n C.int) {
+	C.TFE_OpSetAttrBoolList(op.c, name, values, n)
+}
+
+func (op eagerOp) setType(name *C.char, value C.TF_DataType) {
+	C.TFE_OpSetAttrType(op.c, name, value)
+}
+
+func (op eagerOp) setTypeList(name *C.char, values *C.TF_DataType, n C.int) {
+	C.TFE_OpSetAttrTypeList(op.c, name, values, n)
+}
+
+func (op eagerOp) setTensor(name *C.char, value *Tensor, status *status) {
+	C.TFE_OpSetAttrTensor(op.c, name, value.c, status.c)
+}
+
+// setTensorList sets the serialized list of tensors, since the C API offers
+// no setter for lists of tensors of eager operations.
+func (op eagerOp) setTensorList(name *C.char, values []*Tensor, status *status) {
+	list := &pbs.AttrValue_ListValue{}
+	for _, v := range values {
+		pb, err := v.ToProto()
+		if err != nil {
+			cMsg := C.CString(err.Error())
+			C.TF_SetStatus(status.c, C.TF_INVALID_ARGUMENT, cMsg)
+			C.free(unsafe.Pointer(cMsg))
+			return
+		}
+		list.Tensor = append(list.Tensor, pb)
+	}
+	b := pbs.MustMarshal(&pbs.AttrValue{Value: &pbs.AttrValue_List{List: list}})
+	op.setValueProto(name, unsafe.Pointer(&b[0]), C.size_t(len(b)), status)
+}
+
+func (op eagerOp) setShape(name *C.char, dims *C.int64_t, ndims C.int, status *status) {
+	C.TFE_OpSetAttrShape(op.c, name, dims, ndims, status.c)
+}
+
+func (op eagerOp) setShapeList(name *C.char, flatDims *C.int64_t, ndims *C.int, n C.int, status *status) {
+	if n == 0 {
+		C.TFE_OpSetAttrShapeList(op.c, name, nil, nil, 0, status.c)
+		return
+	}
+	C.TFE_OpSetAttrShapeList_Helper(op.c, name, flatDims, ndims, n, status.c)
+}
+
+func (op eagerOp) setFuncName(name *C.char, value *C.char, length C.size_t) {
+	C.TFE_OpSetAttrFunctionName(op.c, name, value, length)
+}
+
+func (op eagerOp) setValueProto(name *C.char, proto unsafe.Pointer, length C.size_t, status *status) {
+	C.TFE_OpSetAttrValueProto(op.c, name, proto, length, status.c)
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tensorflow
+
+import (
+	"reflect"
+	"testing"
+)
+
+func mustTensorHandle(t *testing.T, value interface{}) *TensorHandle {
+	t.Helper()
+	th, err := NewTensorHandle(mustTensor(t, value))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return th
+}
+
+func checkHandleValue(t *testing.T, th *TensorHandle, want interface{}) {
+	t.Helper()
+	tensor, err := th.ToTensor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := tensor.Value(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %v, want %v", got, want)
+	}
+}
+
+func TestContextExecute(t *testing.T) {
+	ctx, err := NewContext(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := mustTensorHandle(t, []int32{1, 2})
+	b := mustTensorHandle(t, []int32{3, 4})
+
+	outputs, err := ctx.Execute(EagerOpSpec{Type: "Add"}, a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outputs) != 1 {
+		t.Fatalf("Got %d outputs, want 1", len(outputs))
+	}
+	checkHandleValue(t, outputs[0], []int32{4, 6})
+
+	// list inputs and attributes
+	outputs, err = ctx.Execute(EagerOpSpec{Type: "ConcatV2"}, TensorHandleList{a, b, a}, mustTensorHandle(t, int32(0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkHandleValue(t, outputs[0], []int32{1, 2, 3, 4, 1, 2})
+	outputs, err = ctx.Execute(EagerOpSpec{
+		Type:  "Cast",
+		Attrs: map[string]interface{}{"DstT": Float},
+	}, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkHandleValue(t, outputs[0], []float32{1, 2})
+
+	// the number of outputs depends on an attribute
+	outputs, err = ctx.Execute(EagerOpSpec{
+		Type:  "Unpack",
+		Attrs: map[string]interface{}{"num": int64(2)},
+	}, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outputs) != 2 {
+		t.Fatalf("Got %d outputs, want 2", len(outputs))
+	}
+	checkHandleValue(t, outputs[1], int32(2))
+
+	// no outputs
+	outputs, err = ctx.Execute(EagerOpSpec{Type: "NoOp"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outputs) != 0 {
+		t.Errorf("Got %d outputs, want none", len(outputs))
+	}
+
+	if _, err := ctx.Execute(EagerOpSpec{Type: "Add"}, a, mustTensorHandle(t, []float32{1, 2})); err == nil {
+		t.Errorf("Execute with mismatching input types did not fail")
+	}
+	if _, err := ctx.Execute(EagerOpSpec{Type: "NoSuchOp"}); err == nil {
+		t.Errorf("Execute of an unknown operation did not fail")
+	}
+}
+
+func TestContextExecuteShapeAttr(t *testing.T) {
+	ctx, err := NewContext(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputs, err := ctx.Execute(EagerOpSpec{
+		Type: "Fill",
+	}, mustTensorHandle(t, []int32{2, 1}), mustTensorHandle(t, "x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkHandleValue(t, outputs[0], [][]string{{"x"}, {"x"}})
+
+	outputs, err = ctx.Execute(EagerOpSpec{
+		Type: "EnsureShape",
+		Attrs: map[string]interface{}{
+			"shape": MakeShape(2, -1),
+		},
+	}, outputs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shape, err := outputs[0].Shape(); err != nil || !reflect.DeepEqual(shape, []int64{2, 1}) {
+		t.Errorf("Got shape %v (%v), want [2 1]", shape, err)
+	}
+}
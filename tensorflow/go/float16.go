@@ -0,0 +1,196 @@
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tensorflow
+
+import (
+	"math"
+	"strconv"
+)
+
+// Float16 is an IEEE 754 half precision floating point value.
+// It is the Go element type of Half tensors.
+type Float16 uint16
+
+// NewFloat16 converts f to the nearest Float16 value, with ties to even.
+func NewFloat16(f float32) Float16 {
+	return Float16(minifloatFormat{expBits: 5, mantBits: 10}.fromFloat32(f))
+}
+
+// Float32 returns the value of h as float32 (which is exact).
+func (h Float16) Float32() float32 {
+	return minifloatFormat{expBits: 5, mantBits: 10}.toFloat32(uint16(h))
+}
+
+// String formats h like a float32 and implements [fmt.Stringer].
+func (h Float16) String() string { return formatFloat32(h.Float32()) }
+
+// BFloat16 is a brain floating point value, i.e. a float32 truncated to
+// its upper 16 bits. It is the Go element type of Bfloat16 tensors.
+type BFloat16 uint16
+
+// NewBFloat16 converts f to the nearest BFloat16 value, with ties to even.
+func NewBFloat16(f float32) BFloat16 {
+	bits := math.Float32bits(f)
+	if f != f { // keep the sign and make sure the NaN stays quiet
+		return BFloat16(bits>>16 | 0x40)
+	}
+	bits += 0x7fff + (bits>>16)&1
+	return BFloat16(bits >> 16)
+}
+
+// Float32 returns the value of b as float32 (which is exact).
+func (b BFloat16) Float32() float32 { return math.Float32frombits(uint32(b) << 16) }
+
+// String formats b like a float32 and implements [fmt.Stringer].
+func (b BFloat16) String() string { return formatFloat32(b.Float32()) }
+
+// Float8E5M2 is an 8-bit floating point value with 5 exponent and 2 mantissa
+// bits which supports infinities and NaNs like IEEE 754 formats.
+// It is the Go element type of Float8e5m2 tensors.
+type Float8E5M2 uint8
+
+// NewFloat8E5M2 converts f to the nearest Float8E5M2 value, with ties to even.
+// Values beyond the largest finite value of 57344 become infinite.
+func NewFloat8E5M2(f float32) Float8E5M2 {
+	return Float8E5M2(minifloatFormat{expBits: 5, mantBits: 2}.fromFloat32(f))
+}
+
+// Float32 returns the value of e as float32 (which is exact).
+func (e Float8E5M2) Float32() float32 {
+	return minifloatFormat{expBits: 5, mantBits: 2}.toFloat32(uint16(e))
+}
+
+// String formats e like a float32 and implements [fmt.Stringer].
+func (e Float8E5M2) String() string { return formatFloat32(e.Float32()) }
+
+// Float8E4M3FN is an 8-bit floating point value with 4 exponent and 3
+// mantissa bits. It has no infinities and only the bit patterns with
+// all exponent and mantissa bits set are NaNs ("finite NaNs").
+// It is the Go element type of Float8e4m3fn tensors.
+type Float8E4M3FN uint8
+
+// NewFloat8E4M3FN converts f to the nearest Float8E4M3FN value, with ties to
+// even. Infinities and values beyond the largest finite value of 448 become NaN.
+func NewFloat8E4M3FN(f float32) Float8E4M3FN {
+	return Float8E4M3FN(minifloatFormat{expBits: 4, mantBits: 3, finiteOnly: true}.fromFloat32(f))
+}
+
+// Float32 returns the value of e as float32 (which is exact).
+func (e Float8E4M3FN) Float32() float32 {
+	return minifloatFormat{expBits: 4, mantBits: 3, finiteOnly: true}.toFloat32(uint16(e))
+}
+
+// String formats e like a float32 and implements [fmt.Stringer].
+func (e Float8E4M3FN) String() string { return formatFloat32(e.Float32()) }
+
+func formatFloat32(f float32) string {
+	return strconv.FormatFloat(float64(f), 'g', -1, 32)
+}
+
+// minifloatFormat describes a binary floating point format that is smaller
+// than float32 and has a sign bit followed by its exponent and mantissa bits.
+// The exponent bias is half of the exponent range like in IEEE 754 formats.
+// Formats with finiteOnly set have no infinities and use the maximal exponent
+// also for finite values, where only the all-ones mantissa encodes NaN.
+type minifloatFormat struct {
+	expBits, mantBits uint
+	finiteOnly        bool
+}
+
+func (m minifloatFormat) bias() int { return 1<<(m.expBits-1) - 1 }
+
+// fromFloat32 returns the bits of the value of format m
+// that is nearest to f, with ties to even.
+func (m minifloatFormat) fromFloat32(f float32) uint16 {
+	bits := math.Float32bits(f)
+	sign := uint16(bits>>31) << (m.expBits + m.mantBits)
+	maxExp := uint16(1)<<m.expBits - 1
+	nan := sign | maxExp<<m.mantBits | 1<<(m.mantBits-1)
+	inf := sign | maxExp<<m.mantBits
+	if m.finiteOnly {
+		nan = sign | (1<<(m.expBits+m.mantBits) - 1)
+		inf = nan
+	}
+	exp32 := int(bits>>23) & 0xff
+	switch {
+	case f != f:
+		return nan
+	case exp32 == 0xff:
+		return inf
+	case exp32 == 0:
+		return sign // float32 subnormals are too small for all these formats
+	}
+
+	// round the mantissa with its implicit bit to the target precision
+	mant := bits&0x7fffff | 1<<23
+	exp := exp32 - 127 + m.bias()
+	shift := 23 - int(m.mantBits)
+	if exp < 1 { // subnormal in the target format
+		shift += 1 - exp
+		exp = 0
+	}
+	if shift > 24 {
+		return sign // less than half of the smallest subnormal
+	}
+	rounded := mant >> shift
+	rem, half := mant&(1<<shift-1), uint32(1)<<(shift-1)
+	if rem > half || rem == half && rounded&1 == 1 {
+		rounded++
+	}
+	// rounding up may carry into the exponent which this addition handles
+	// and a subnormal carries into the smallest normal number just as well
+	val := uint32(rounded)
+	if exp > 0 {
+		val = uint32(exp)<<m.mantBits + rounded - 1<<m.mantBits
+	}
+
+	if m.finiteOnly {
+		if val >= 1<<(m.expBits+m.mantBits)-1 {
+			return nan
+		}
+	} else if val >= uint32(maxExp)<<m.mantBits {
+		return inf
+	}
+	return sign | uint16(val)
+}
+
+// toFloat32 returns the float32 value of the bits of format m.
+func (m minifloatFormat) toFloat32(bits uint16) float32 {
+	mantMask := uint16(1)<<m.mantBits - 1
+	maxExp := int(1)<<m.expBits - 1
+	neg := bits>>(m.expBits+m.mantBits)&1 == 1
+	exp := int(bits>>m.mantBits) & maxExp
+	mant := bits & mantMask
+
+	var val float64
+	switch {
+	case m.finiteOnly && exp == maxExp && mant == mantMask:
+		val = math.NaN()
+	case !m.finiteOnly && exp == maxExp && mant != 0:
+		val = math.NaN()
+	case !m.finiteOnly && exp == maxExp:
+		val = math.Inf(1)
+	case exp == 0: // zero or subnormal
+		val = math.Ldexp(float64(mant), 1-m.bias()-int(m.mantBits))
+	default:
+		val = math.Ldexp(float64(mant|1<<m.mantBits), exp-m.bias()-int(m.mantBits))
+	}
+	if neg {
+		val = -val
+	}
+	return float32(val)
+}
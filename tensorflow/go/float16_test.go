@@ -0,0 +1,183 @@
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tensorflow
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestFloat16Conversion(t *testing.T) {
+	tests := []struct {
+		f    float32
+		bits uint16
+	}{
+		{1, 0x3c00},
+		{-2, 0xc000},
+		{0.1, 0x2e66},
+		{65504, 0x7bff},                       // largest finite value
+		{65519, 0x7bff},                       // rounds down to the largest finite value
+		{65520, 0x7c00},                       // rounds up to infinity
+		{6.1035156e-05, 0x0400},               // smallest normal value
+		{float32(math.Ldexp(1, -24)), 0x0001}, // smallest subnormal value
+		{float32(math.Ldexp(1, -25)), 0x0000}, // tie rounds to even zero
+		{float32(math.Ldexp(3, -26)), 0x0001}, // rounds up to the smallest subnormal
+		{float32(math.Inf(-1)), 0xfc00},
+	}
+	for _, test := range tests {
+		if got := NewFloat16(test.f); uint16(got) != test.bits {
+			t.Errorf("NewFloat16(%v) = %#04x, want %#04x", test.f, uint16(got), test.bits)
+		}
+	}
+	if f := NewFloat16(float32(math.NaN())).Float32(); f == f {
+		t.Errorf("NaN converted to %v", f)
+	}
+	// all finite values survive a roundtrip
+	for i := 0; i < 1<<16; i++ {
+		h := Float16(i)
+		if f := h.Float32(); f == f && NewFloat16(f) != h {
+			t.Errorf("Float16 %#04x changed to %#04x in a roundtrip via %v", i, uint16(NewFloat16(f)), f)
+		}
+	}
+}
+
+func TestBFloat16Conversion(t *testing.T) {
+	tests := []struct {
+		bits32 uint32
+		bits   uint16
+	}{
+		{0x3f800000, 0x3f80}, // 1
+		{0x3f808000, 0x3f80}, // tie rounds to even
+		{0x3f818000, 0x3f82}, // tie rounds to even
+		{0x3f808001, 0x3f81}, // above the tie rounds up
+		{0x7f7fffff, 0x7f80}, // largest float32 rounds to infinity
+	}
+	for _, test := range tests {
+		f := math.Float32frombits(test.bits32)
+		if got := NewBFloat16(f); uint16(got) != test.bits {
+			t.Errorf("NewBFloat16(%v) = %#04x, want %#04x", f, uint16(got), test.bits)
+		}
+	}
+	if got, want := NewBFloat16(-1.5).Float32(), float32(-1.5); got != want {
+		t.Errorf("Got %v, want %v", got, want)
+	}
+	if f := NewBFloat16(float32(math.NaN())).Float32(); f == f {
+		t.Errorf("NaN converted to %v", f)
+	}
+}
+
+func TestFloat8Conversion(t *testing.T) {
+	e5m2Tests := []struct {
+		f    float32
+		bits uint8
+	}{
+		{1, 0x3c},
+		{-3.5, 0xc3},
+		{1.125, 0x3c}, // tie rounds to even
+		{1.375, 0x3e}, // tie rounds to even
+		{57344, 0x7b}, // largest finite value
+		{61440, 0x7c}, // rounds up to infinity
+		{float32(math.Ldexp(1, -16)), 0x01},
+	}
+	for _, test := range e5m2Tests {
+		if got := NewFloat8E5M2(test.f); uint8(got) != test.bits {
+			t.Errorf("NewFloat8E5M2(%v) = %#02x, want %#02x", test.f, uint8(got), test.bits)
+		}
+	}
+	e4m3Tests := []struct {
+		f    float32
+		bits uint8
+	}{
+		{1, 0x38},
+		{-0.015625, 0x88},
+		{448, 0x7e},                              // largest finite value
+		{464, 0x7e},                              // tie rounds to even
+		{500, 0x7f},                              // no infinities, so it becomes NaN
+		{float32(math.Inf(1)), 0x7f},             // no infinities, so it becomes NaN
+		{-500, 0xff},                             // keeps the sign of the overflow
+		{float32(math.Inf(-1)), 0xff},            // keeps the sign of the infinity
+		{math.Float32frombits(0xffc00000), 0xff}, // keeps the sign of the NaN
+		{float32(math.Ldexp(1, -9)), 0x01},       // smallest subnormal value
+		{float32(math.Ldexp(1, -10)), 0x00},      // tie rounds to even zero
+	}
+	for _, test := range e4m3Tests {
+		if got := NewFloat8E4M3FN(test.f); uint8(got) != test.bits {
+			t.Errorf("NewFloat8E4M3FN(%v) = %#02x, want %#02x", test.f, uint8(got), test.bits)
+		}
+	}
+	for i := 0; i < 1<<8; i++ {
+		if e := Float8E5M2(i); e.Float32() == e.Float32() && NewFloat8E5M2(e.Float32()) != e {
+			t.Errorf("Float8E5M2 %#02x changed in a roundtrip via %v", i, e.Float32())
+		}
+		if e := Float8E4M3FN(i); e.Float32() == e.Float32() && NewFloat8E4M3FN(e.Float32()) != e {
+			t.Errorf("Float8E4M3FN %#02x changed in a roundtrip via %v", i, e.Float32())
+		}
+	}
+}
+
+func TestHalfTensor(t *testing.T) {
+	value := [][]Float16{{NewFloat16(1), NewFloat16(-0.5)}, {NewFloat16(3), NewFloat16(1e4)}}
+	tensor, err := NewTensor(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := tensor.DataType(); got != Half {
+		t.Fatalf("Got type %v, want %v", got, Half)
+	}
+	if got := tensor.Value(); !reflect.DeepEqual(got, value) {
+		t.Errorf("Got %v, want %v", got, value)
+	}
+
+	// serialize and deserialize the tensor
+	buf := new(bytes.Buffer)
+	if _, err := tensor.WriteContentsTo(buf); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadTensor(Half, tensor.Shape(), buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := read.Value(); !reflect.DeepEqual(got, value) {
+		t.Errorf("Got %v, want %v", got, value)
+	}
+
+	// cast the tensor inside a graph
+	g := NewGraph()
+	inp := _Placeholder(g, "input", Half)
+	cast, err := g.AddOperation(OpSpec{
+		Type:  "Cast",
+		Input: []Input{inp},
+		Attrs: map[string]interface{}{"DstT": Float},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewSession(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	fetched, err := s.Run(FeedMap{inp: tensor}, []Output{cast.Output(0)}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fetched[0].Value(), [][]float32{{1, -0.5}, {3, 1e4}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %v, want %v", got, want)
+	}
+}
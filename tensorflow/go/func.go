@@ -87,10 +87,19 @@ func (g *Graph) Functions() []*Func {
 }
 
 // AsFunc returns the tensorflow function ([Func]) corresponding to the graph.
+//
+// Only the operations needed to compute the outputs from the inputs,
+// including their control dependencies, become part of the function, so
+// other operations of the graph, like initializers, are ignored. It fails if
+// the outputs depend on placeholders which are not inputs.
 func (g *Graph) AsFunc(name string, inputs, outputs []Output, outNames []string, desc string) (*Func, error) {
 	if numOuts, numNames := len(outputs), len(outNames); numOuts != numNames && numNames != 0 {
 		return nil, fmt.Errorf("mismatch of outputs and their names: %d vs %d", numOuts, numNames)
 	}
+	opers, err := g.funcBody(inputs, outputs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert graph to function %q: %w", name, err)
+	}
 
 	var pInputs *C.TF_Output
 	if len(inputs) > 0 {
@@ -113,9 +122,14 @@ func (g *Graph) AsFunc(name string, inputs, outputs []Output, outNames []string,
 		cOutNames := make([]*C.char, len(outNames))
 		for i, name := range outNames {
 			cOutNames[i] = C.CString(name)
+			defer C.free(unsafe.Pointer(cOutNames[i]))
 		}
 		pOutNames = &cOutNames[0]
 	}
+	var pOpers **C.TF_Operation
+	if len(opers) > 0 {
+		pOpers = &opers[0]
+	}
 
 	cName := C.CString(name)
 	defer C.free(unsafe.Pointer(cName))
@@ -123,24 +137,78 @@ func (g *Graph) AsFunc(name string, inputs, outputs []Output, outNames []string,
 	defer C.free(unsafe.Pointer(cDesc))
 	cHashFnName := C.uchar(1) // name hashing enabled
 
-	// TODO? select subset of ops needed for inputs to outputs
-	cNumOpers := C.int(-1)      // all ops for now -> -1
-	var pOpers **C.TF_Operation // all graph ops for now -> nil
-
 	status := newStatus()
 	fn := C.TF_GraphToFunction(
 		g.c, cName, cHashFnName,
-		cNumOpers, pOpers,
+		C.int(len(opers)), pOpers,
 		C.int(len(inputs)), pInputs,
 		C.int(len(outputs)), pOutputs,
 		pOutNames,
 		nil, cDesc, status.c)
 	if err := status.Err(); err != nil {
-		panic(err)
+		return nil, fmt.Errorf("failed to convert graph to function %q: %w", name, err)
 	}
 	return &Func{fn}, nil
 }
 
+// funcBody returns the operations needed to compute the outputs from the
+// inputs in the order of the graph. The operations producing the inputs are
+// replaced by the arguments of the function and thus excluded.
+func (g *Graph) funcBody(inputs, outputs []Output) ([]*C.TF_Operation, error) {
+	fed := make(map[C.TF_Output]bool, len(inputs))
+	inputOps := make(map[*C.TF_Operation]bool, len(inputs))
+	for i, in := range inputs {
+		if in.Op == nil {
+			return nil, fmt.Errorf("input %d has no operation", i)
+		}
+		fed[in.c()] = true
+		inputOps[in.Op.c] = true
+	}
+	var pending []*Operation
+	for i, out := range outputs {
+		if out.Op == nil {
+			return nil, fmt.Errorf("output %d has no operation", i)
+		}
+		if !fed[out.c()] {
+			pending = append(pending, out.Op)
+		}
+	}
+
+	needed := make(map[*C.TF_Operation]bool)
+	for len(pending) > 0 {
+		op := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if needed[op.c] {
+			continue
+		}
+		if inputOps[op.c] {
+			return nil, fmt.Errorf("operation %q produces an input, but its other outputs are needed as well", op.Name())
+		}
+		if t := op.Type(); t == "Placeholder" || t == "PlaceholderV2" {
+			return nil, fmt.Errorf("placeholder %q is needed to compute the outputs, but is not an input", op.Name())
+		}
+		needed[op.c] = true
+		for i, n := 0, op.NumInputs(); i < n; i++ {
+			if in := (Consumer{Op: op, Index: i}).Producer(); !fed[in.c()] {
+				pending = append(pending, in.Op)
+			}
+		}
+		for _, ctrl := range op.ControlInputs() {
+			if !inputOps[ctrl.c] { // control dependencies on inputs are dropped
+				pending = append(pending, ctrl)
+			}
+		}
+	}
+
+	opers := make([]*C.TF_Operation, 0, len(needed))
+	for _, op := range g.Operations() {
+		if needed[op.c] {
+			opers = append(opers, op.c)
+		}
+	}
+	return opers, nil
+}
+
 func (g *Graph) addFunc(fn *Func, name string, inputs ...Input) (*Operation, error) {
 	return g.AddOperation(OpSpec{
 		Type:  fn.Name(),
@@ -19,8 +19,12 @@ package tensorflow
 import (
 	"bytes"
 	"fmt"
+	"sort"
 	"strings"
 	"testing"
+
+	"github.com/hdu-hh/tensorflow/tensorflow/go/pbs"
+	"google.golang.org/protobuf/proto"
 )
 
 // returns a test Func that returns the negative value of the input int8 value
@@ -140,3 +144,55 @@ func TestFuncSignature(t *testing.T) {
 		}
 	}
 }
+
+func TestAsFuncPruning(t *testing.T) {
+	g := NewGraph()
+	x := _Placeholder(g, "x", Int8)
+	y := _Neg(g, "neg", x)
+	check, err := g.AddOperation(OpSpec{Type: "NoOp", Name: "check"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	z, err := g.AddOperation(OpSpec{
+		Type:                "Identity",
+		Name:                "z",
+		Input:               []Input{y},
+		ControlDependencies: []*Operation{check},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// stray operations which must not become part of the function
+	_Neg(g, "stray", _Placeholder(g, "unfed", Int8))
+	_Const(g, "init", int8(1))
+
+	fn, err := g.AsFunc("pruned", []Output{x}, []Output{z.Output(0)}, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fn.Delete()
+	var buf bytes.Buffer
+	if _, err := fn.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var def pbs.FunctionDef
+	if err := proto.Unmarshal(buf.Bytes(), &def); err != nil {
+		t.Fatal(err)
+	}
+	var nodes []string
+	for _, node := range def.GetNodeDef() {
+		nodes = append(nodes, node.GetName())
+	}
+	sort.Strings(nodes)
+	if got, want := strings.Join(nodes, ","), "check,neg,z"; got != want {
+		t.Errorf("Got function nodes %q, want %q", got, want)
+	}
+
+	_, err = g.AsFunc("unfed", []Output{x}, []Output{g.Operation("stray").Output(0)}, nil, "")
+	if err == nil || !strings.Contains(err.Error(), `placeholder "unfed"`) {
+		t.Errorf("Got error %v for an unfed placeholder", err)
+	}
+	if _, err := g.AsFunc("nil", []Output{x}, []Output{{}}, nil, ""); err == nil {
+		t.Errorf("AsFunc with an invalid output did not fail")
+	}
+}
@@ -0,0 +1,287 @@
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tensorflow
+
+// #include "tensorflow/c/c_api.h"
+import "C"
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hdu-hh/tensorflow/tensorflow/go/pbs"
+	"google.golang.org/protobuf/proto"
+)
+
+// GradientTape records the operations executed eagerly in a Context to
+// compute gradients of their results.
+//
+// Only the operations depending on watched tensor handles are recorded.
+// They are mirrored in a Graph, so that the gradients are computed with the
+// gradient functions registered for [Graph.AddGradients]. E.g.:
+//
+//	tape := ctx.NewGradientTape()
+//	tape.Watch(x)
+//	y, _ := ctx.Execute(EagerOpSpec{Type: "Square"}, x)
+//	grads, _ := tape.Gradient(y[0], x) // 2 * x
+type GradientTape struct {
+	ctx *Context
+
+	mu      sync.Mutex
+	graph   *Graph
+	outputs map[*TensorHandle]Output      // the mirrored tensor handles
+	handles map[C.TF_Output]*TensorHandle // the tensor handles of mirrored outputs
+	traced  map[*TensorHandle]bool        // watched or computed from watched handles
+	nodes   int
+	err     error // the first failure to record an operation
+}
+
+// NewGradientTape creates a GradientTape which records the operations
+// executed in the Context until it is stopped.
+func (c *Context) NewGradientTape() *GradientTape {
+	t := &GradientTape{
+		ctx:     c,
+		graph:   NewGraph(),
+		outputs: make(map[*TensorHandle]Output),
+		handles: make(map[C.TF_Output]*TensorHandle),
+		traced:  make(map[*TensorHandle]bool),
+	}
+	c.mu.Lock()
+	c.tapes = append(c.tapes[:len(c.tapes):len(c.tapes)], t)
+	c.mu.Unlock()
+	return t
+}
+
+// Watch marks tensor handles for the computation of gradients, such that
+// operations using them are recorded. Variables are watched by watching
+// the values read from them.
+func (t *GradientTape) Watch(handles ...*TensorHandle) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, th := range handles {
+		if _, err := t.output(th); err != nil && t.err == nil {
+			t.err = err
+		}
+		t.traced[th] = true
+	}
+}
+
+// Stop stops recording operations. The gradients of the operations recorded
+// so far can still be computed.
+func (t *GradientTape) Stop() {
+	t.ctx.mu.Lock()
+	defer t.ctx.mu.Unlock()
+	tapes := make([]*GradientTape, 0, len(t.ctx.tapes))
+	for _, tape := range t.ctx.tapes {
+		if tape != t {
+			tapes = append(tapes, tape)
+		}
+	}
+	t.ctx.tapes = tapes
+}
+
+// Gradient computes the partial derivatives of target with respect to each
+// of the sources, which must be watched or computed by recorded operations.
+// It executes the operations computing the gradients eagerly without
+// recording them and stops recording operations.
+func (t *GradientTape) Gradient(target *TensorHandle, sources ...*TensorHandle) ([]*TensorHandle, error) {
+	t.Stop()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.err != nil {
+		return nil, t.err
+	}
+	if !t.traced[target] {
+		return nil, fmt.Errorf("target was not computed from watched tensors")
+	}
+	x := make([]Output, len(sources))
+	for i, th := range sources {
+		if !t.traced[th] {
+			return nil, fmt.Errorf("source %d was neither watched nor computed from watched tensors", i)
+		}
+		x[i] = t.outputs[th]
+	}
+
+	recorded := make(map[string]bool)
+	for _, op := range t.graph.Operations() {
+		recorded[op.Name()] = true
+	}
+	prefix := fmt.Sprintf("gradients_%d", t.nodes)
+	t.nodes++
+	dy, err := t.graph.AddGradients(prefix, []Output{t.outputs[target]}, x, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add gradients: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := t.graph.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	var def pbs.GraphDef
+	if err := proto.Unmarshal(buf.Bytes(), &def); err != nil {
+		return nil, err
+	}
+	for _, node := range def.GetNode() {
+		if !recorded[node.GetName()] {
+			if err := t.executeNode(node); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	grads := make([]*TensorHandle, len(dy))
+	for i, out := range dy {
+		th, ok := t.handles[out.c()]
+		if !ok {
+			return nil, fmt.Errorf("gradient %d was not computed", i)
+		}
+		grads[i] = th
+	}
+	return grads, nil
+}
+
+// executeNode executes an operation added to the mirrored graph by
+// AddGradients.
+func (t *GradientTape) executeNode(node *pbs.NodeDef) error {
+	var inputs []EagerInput
+	for _, name := range node.GetInput() {
+		if strings.HasPrefix(name, "^") {
+			continue
+		}
+		out, err := t.graph.outputByName(name)
+		if err != nil {
+			return err
+		}
+		th, ok := t.handles[out.c()]
+		if !ok {
+			return fmt.Errorf("input %q of operation %q was not computed", name, node.GetName())
+		}
+		inputs = append(inputs, th)
+	}
+	attrs := make(map[string]interface{}, len(node.GetAttr()))
+	for name, value := range node.GetAttr() {
+		attrs[name] = value
+	}
+	op := t.graph.Operation(node.GetName())
+	outputs, err := t.ctx.execute(EagerOpSpec{
+		Type:       node.GetOp(),
+		Attrs:      attrs,
+		NumOutputs: op.NumOutputs(),
+	}, inputs)
+	if err != nil {
+		return fmt.Errorf("failed to compute gradients: %w", err)
+	}
+	for i, th := range outputs {
+		t.handles[op.Output(i).c()] = th
+	}
+	return nil
+}
+
+// record mirrors an executed operation if it depends on traced handles.
+func (t *GradientTape) record(spec EagerOpSpec, inputs []EagerInput, outputs []*TensorHandle) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.err != nil || !t.dependsOnTraced(inputs) {
+		return
+	}
+	graphInputs := make([]Input, len(inputs))
+	for i, input := range inputs {
+		switch input := input.(type) {
+		case *TensorHandle:
+			out, err := t.output(input)
+			if err != nil {
+				t.err = err
+				return
+			}
+			graphInputs[i] = out
+		case TensorHandleList:
+			list := make(OutputList, len(input))
+			for j, th := range input {
+				out, err := t.output(th)
+				if err != nil {
+					t.err = err
+					return
+				}
+				list[j] = out
+			}
+			graphInputs[i] = list
+		}
+	}
+	op, err := t.graph.AddOperation(OpSpec{
+		Type:  spec.Type,
+		Name:  fmt.Sprintf("%s_%d", spec.Type, t.nodes),
+		Input: graphInputs,
+		Attrs: spec.Attrs,
+	})
+	t.nodes++
+	if err != nil {
+		t.err = fmt.Errorf("failed to record operation %q: %w", spec.Type, err)
+		return
+	}
+	for i, th := range outputs {
+		if i < op.NumOutputs() {
+			t.outputs[th] = op.Output(i)
+			t.handles[op.Output(i).c()] = th
+			t.traced[th] = true
+		}
+	}
+}
+
+func (t *GradientTape) dependsOnTraced(inputs []EagerInput) bool {
+	for _, input := range inputs {
+		switch input := input.(type) {
+		case *TensorHandle:
+			if t.traced[input] {
+				return true
+			}
+		case TensorHandleList:
+			for _, th := range input {
+				if t.traced[th] {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// output returns the mirrored output of a tensor handle. Handles which were
+// not computed by recorded operations are mirrored by placeholders.
+func (t *GradientTape) output(th *TensorHandle) (Output, error) {
+	if out, ok := t.outputs[th]; ok {
+		return out, nil
+	}
+	attrs := map[string]interface{}{"dtype": th.DataType()}
+	if dims, err := th.Shape(); err == nil {
+		attrs["shape"] = MakeShape(dims...)
+	}
+	op, err := t.graph.AddOperation(OpSpec{
+		Type:  "Placeholder",
+		Name:  fmt.Sprintf("input_%d", t.nodes),
+		Attrs: attrs,
+	})
+	t.nodes++
+	if err != nil {
+		return Output{}, err
+	}
+	out := op.Output(0)
+	t.outputs[th] = out
+	t.handles[out.c()] = th
+	return out, nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tensorflow
+
+import (
+	"testing"
+
+	"github.com/hdu-hh/tensorflow/tensorflow/go/pbs"
+)
+
+func TestGradientTape(t *testing.T) {
+	ctx, err := NewContext(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x := mustTensorHandle(t, []float32{1, 2, 3})
+	w := mustTensorHandle(t, []float32{2, 2, 2})
+	c := mustTensorHandle(t, float32(5))
+
+	tape := ctx.NewGradientTape()
+	tape.Watch(x)
+	// operations independent of x are not recorded
+	c2, err := ctx.Execute(EagerOpSpec{Type: "Square"}, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xw, err := ctx.Execute(EagerOpSpec{Type: "Mul"}, x, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sq, err := ctx.Execute(EagerOpSpec{Type: "Square"}, xw[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	// y = sum((x * w)^2)
+	y, err := ctx.Execute(EagerOpSpec{Type: "Sum"}, sq[0], mustTensorHandle(t, []int32{0}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	grads, err := tape.Gradient(y[0], x, xw[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkHandleValue(t, grads[0], []float32{8, 16, 24})
+	checkHandleValue(t, grads[1], []float32{4, 8, 12})
+	if _, err := tape.Gradient(y[0], w); err == nil {
+		t.Errorf("Gradient with respect to an unwatched tensor did not fail")
+	}
+	if _, err := tape.Gradient(c2[0], x); err == nil {
+		t.Errorf("Gradient of an unrecorded tensor did not fail")
+	}
+
+	// the tape stopped recording
+	y2, err := ctx.Execute(EagerOpSpec{Type: "Neg"}, x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tape.Gradient(y2[0], x); err == nil {
+		t.Errorf("Gradient of an operation executed after stopping did not fail")
+	}
+}
+
+func TestExecuteAttrValue(t *testing.T) {
+	ctx, err := NewContext(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputs, err := ctx.Execute(EagerOpSpec{
+		Type:  "Cast",
+		Attrs: map[string]interface{}{"DstT": &pbs.AttrValue{Value: &pbs.AttrValue_Type{Type: pbs.DataType_DT_INT64}}},
+	}, mustTensorHandle(t, []float32{1.5, 2}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkHandleValue(t, outputs[0], []int64{1, 2})
+}
@@ -45,7 +45,11 @@ import (
 	"fmt"
 	"io"
 	"runtime"
+	"strconv"
+	"strings"
 	"unsafe"
+
+	"github.com/hdu-hh/tensorflow/tensorflow/go/pbs"
 )
 
 // Graph represents a computation graph. Graphs may be shared between sessions.
@@ -136,6 +140,15 @@ func getBufferAsSlice(buf *C.TF_Buffer) ([]byte, error) {
 	return slice, nil
 }
 
+// newBufferFromSlice returns a TF_Buffer holding a copy of b.
+// Callers must deallocate it with TF_DeleteBuffer.
+func newBufferFromSlice(b []byte) *C.TF_Buffer {
+	if len(b) == 0 {
+		return C.TF_NewBuffer()
+	}
+	return C.TF_NewBufferFromString(unsafe.Pointer(&b[0]), C.size_t(len(b)))
+}
+
 // ImportWithOptions imports the nodes and edges from a serialized
 // representation of a [pbs.GraphDef] protocol buffer into the graph.
 //
@@ -199,6 +212,27 @@ func (g *Graph) Operation(name string) *Operation {
 	return &Operation{cop, g}
 }
 
+// outputByName returns the Output for a tensor name of the form
+// "operation:index" or just "operation" which implies the index 0.
+func (g *Graph) outputByName(name string) (Output, error) {
+	opName, index := name, 0
+	if i := strings.LastIndexByte(name, ':'); i >= 0 {
+		var err error
+		if index, err = strconv.Atoi(name[i+1:]); err != nil || index < 0 {
+			return Output{}, fmt.Errorf("invalid tensor name %q", name)
+		}
+		opName = name[:i]
+	}
+	op := g.Operation(opName)
+	if op == nil {
+		return Output{}, fmt.Errorf("operation %q not found for tensor %q", opName, name)
+	}
+	if index >= op.NumOutputs() {
+		return Output{}, fmt.Errorf("operation %q has no output %d", opName, index)
+	}
+	return op.Output(index), nil
+}
+
 // Operations returns a list of all operations in the graph
 func (g *Graph) Operations() []Operation {
 	var pos C.size_t
@@ -348,7 +382,7 @@ func (g *Graph) AddOperation(args OpSpec) (*Operation, error) {
 	}
 	status := newStatus()
 	for name, value := range args.Attrs {
-		if err := setAttr(cdesc, status, name, value); err != nil {
+		if err := setAttr(opDescription{cdesc}, status, name, value); err != nil {
 			// Memory leak here as the TF_OperationDescription
 			// object will not be cleaned up. At the time of this
 			// writing, this was next to impossible since it
@@ -372,13 +406,116 @@ func (g *Graph) AddOperation(args OpSpec) (*Operation, error) {
 	return &Operation{c, g}, nil
 }
 
-func setAttr(cdesc *C.TF_OperationDescription, status *status, name string, value interface{}) error {
+// attrSetter sets the attributes of an operation under construction. It is
+// implemented for operations added to a graph and for eager operations, so
+// that both share the conversion of Go values in setAttr.
+type attrSetter interface {
+	setString(name *C.char, value unsafe.Pointer, length C.size_t)
+	setStringList(name *C.char, values *unsafe.Pointer, lengths *C.size_t, n C.int)
+	setInt(name *C.char, value C.int64_t)
+	setIntList(name *C.char, values *C.int64_t, n C.int)
+	setFloat(name *C.char, value C.float)
+	setFloatList(name *C.char, values *C.float, n C.int)
+	setBool(name *C.char, value C.uchar)
+	setBoolList(name *C.char, values *C.uchar, n C.int)
+	setType(name *C.char, value C.TF_DataType)
+	setTypeList(name *C.char, values *C.TF_DataType, n C.int)
+	setTensor(name *C.char, value *Tensor, status *status)
+	setTensorList(name *C.char, values []*Tensor, status *status)
+	setShape(name *C.char, dims *C.int64_t, ndims C.int, status *status)
+	setShapeList(name *C.char, flatDims *C.int64_t, ndims *C.int, n C.int, status *status)
+	setFuncName(name *C.char, value *C.char, length C.size_t)
+	setValueProto(name *C.char, proto unsafe.Pointer, length C.size_t, status *status)
+}
+
+// opDescription sets the attributes of an operation added to a graph.
+type opDescription struct {
+	c *C.TF_OperationDescription
+}
+
+func (d opDescription) setString(name *C.char, value unsafe.Pointer, length C.size_t) {
+	C.TF_SetAttrString(d.c, name, value, length)
+}
+
+func (d opDescription) setStringList(name *C.char, values *unsafe.Pointer, lengths *C.size_t, n C.int) {
+	C.TF_SetAttrStringList(d.c, name, values, lengths, n)
+}
+
+func (d opDescription) setInt(name *C.char, value C.int64_t) {
+	C.TF_SetAttrInt(d.c, name, value)
+}
+
+func (d opDescription) setIntList(name *C.char, values *C.int64_t, n C.int) {
+	C.TF_SetAttrIntList(d.c, name, values, n)
+}
+
+func (d opDescription) setFloat(name *C.char, value C.float) {
+	C.TF_SetAttrFloat(d.c, name, value)
+}
+
+func (d opDescription) setFloatList(name *C.char, values *C.float, n C.int) {
+	C.TF_SetAttrFloatList(d.c, name, values, n)
+}
+
+func (d opDescription) setBool(name *C.char, value C.uchar) {
+	C.TF_SetAttrBool(d.c, name, value)
+}
+
+func (d opDescription) setBoolList(name *C.char, values *C.uchar, n C.int) {
+	C.TF_SetAttrBoolList(d.c, name, values, n)
+}
+
+func (d opDescription) setType(name *C.char, value C.TF_DataType) {
+	C.TF_SetAttrType(d.c, name, value)
+}
+
+func (d opDescription) setTypeList(name *C.char, values *C.TF_DataType, n C.int) {
+	C.TF_SetAttrTypeList(d.c, name, values, n)
+}
+
+func (d opDescription) setTensor(name *C.char, value *Tensor, status *status) {
+	C.TF_SetAttrTensor(d.c, name, value.c, status.c)
+}
+
+func (d opDescription) setTensorList(name *C.char, values []*Tensor, status *status) {
+	list := make([]*C.TF_Tensor, len(values))
+	for i, v := range values {
+		list[i] = v.c
+	}
+	var plist **C.TF_Tensor
+	if len(list) > 0 {
+		plist = &list[0]
+	}
+	C.TF_SetAttrTensorList(d.c, name, plist, C.int(len(list)), status.c)
+}
+
+func (d opDescription) setShape(name *C.char, dims *C.int64_t, ndims C.int, status *status) {
+	C.TF_SetAttrShape(d.c, name, dims, ndims)
+}
+
+func (d opDescription) setShapeList(name *C.char, flatDims *C.int64_t, ndims *C.int, n C.int, status *status) {
+	if n == 0 {
+		C.TF_SetAttrShapeList(d.c, name, nil, nil, 0)
+		return
+	}
+	C.TF_SetAttrShapeList_Helper(d.c, name, flatDims, ndims, n)
+}
+
+func (d opDescription) setFuncName(name *C.char, value *C.char, length C.size_t) {
+	C.TF_SetAttrFuncName(d.c, name, value, length)
+}
+
+func (d opDescription) setValueProto(name *C.char, proto unsafe.Pointer, length C.size_t, status *status) {
+	C.TF_SetAttrValueProto(d.c, name, proto, length, status.c)
+}
+
+func setAttr(setter attrSetter, status *status, name string, value interface{}) error {
 	cAttrName := C.CString(name)
 	defer C.free(unsafe.Pointer(cAttrName))
 	switch value := value.(type) {
 	case string:
 		cstr := C.CString(value)
-		C.TF_SetAttrString(cdesc, cAttrName, unsafe.Pointer(cstr), C.size_t(len(value)))
+		setter.setString(cAttrName, unsafe.Pointer(cstr), C.size_t(len(value)))
 		C.free(unsafe.Pointer(cstr))
 	case []string:
 		size := len(value)
@@ -389,15 +526,15 @@ func setAttr(cdesc *C.TF_OperationDescription, status *status, name string, valu
 			lens[i] = C.size_t(len(s))
 		}
 		if size > 0 {
-			C.TF_SetAttrStringList(cdesc, cAttrName, &list[0], &lens[0], C.int(size))
+			setter.setStringList(cAttrName, &list[0], &lens[0], C.int(size))
 		} else {
-			C.TF_SetAttrStringList(cdesc, cAttrName, nil, nil, 0)
+			setter.setStringList(cAttrName, nil, nil, 0)
 		}
 		for _, s := range list {
 			C.free(s)
 		}
 	case int64:
-		C.TF_SetAttrInt(cdesc, cAttrName, C.int64_t(value))
+		setter.setInt(cAttrName, C.int64_t(value))
 	case []int64:
 		size := len(value)
 		list := make([]C.int64_t, size)
@@ -405,12 +542,12 @@ func setAttr(cdesc *C.TF_OperationDescription, status *status, name string, valu
 			list[i] = C.int64_t(v)
 		}
 		if size > 0 {
-			C.TF_SetAttrIntList(cdesc, cAttrName, &list[0], C.int(size))
+			setter.setIntList(cAttrName, &list[0], C.int(size))
 		} else {
-			C.TF_SetAttrIntList(cdesc, cAttrName, nil, 0)
+			setter.setIntList(cAttrName, nil, 0)
 		}
 	case float32:
-		C.TF_SetAttrFloat(cdesc, cAttrName, C.float(value))
+		setter.setFloat(cAttrName, C.float(value))
 	case []float32:
 		size := len(value)
 		list := make([]C.float, size)
@@ -418,16 +555,16 @@ func setAttr(cdesc *C.TF_OperationDescription, status *status, name string, valu
 			list[i] = C.float(v)
 		}
 		if size > 0 {
-			C.TF_SetAttrFloatList(cdesc, cAttrName, &list[0], C.int(size))
+			setter.setFloatList(cAttrName, &list[0], C.int(size))
 		} else {
-			C.TF_SetAttrFloatList(cdesc, cAttrName, nil, 0)
+			setter.setFloatList(cAttrName, nil, 0)
 		}
 	case bool:
 		v := C.uchar(0)
 		if value {
 			v = 1
 		}
-		C.TF_SetAttrBool(cdesc, cAttrName, v)
+		setter.setBool(cAttrName, v)
 	case []bool:
 		size := len(value)
 		list := make([]C.uchar, size)
@@ -437,34 +574,25 @@ func setAttr(cdesc *C.TF_OperationDescription, status *status, name string, valu
 			}
 		}
 		if size > 0 {
-			C.TF_SetAttrBoolList(cdesc, cAttrName, &list[0], C.int(size))
+			setter.setBoolList(cAttrName, &list[0], C.int(size))
 		} else {
-			C.TF_SetAttrBoolList(cdesc, cAttrName, nil, 0)
+			setter.setBoolList(cAttrName, nil, 0)
 		}
 	case DataType:
-		C.TF_SetAttrType(cdesc, cAttrName, C.TF_DataType(value))
+		setter.setType(cAttrName, C.TF_DataType(value))
 	case []DataType:
 		var list *C.TF_DataType
 		if len(value) > 0 {
 			list = (*C.TF_DataType)(&value[0])
 		}
-		C.TF_SetAttrTypeList(cdesc, cAttrName, list, C.int(len(value)))
+		setter.setTypeList(cAttrName, list, C.int(len(value)))
 	case *Tensor:
-		C.TF_SetAttrTensor(cdesc, cAttrName, value.c, status.c)
+		setter.setTensor(cAttrName, value, status)
 		if err := status.Err(); err != nil {
 			return fmt.Errorf("bad value for attribute %q: %w", name, err)
 		}
 	case []*Tensor:
-		size := len(value)
-		list := make([]*C.TF_Tensor, size)
-		for i, v := range value {
-			list[i] = v.c
-		}
-		var plist **C.TF_Tensor
-		if size > 0 {
-			plist = &list[0]
-		}
-		C.TF_SetAttrTensorList(cdesc, cAttrName, plist, C.int(size), status.c)
+		setter.setTensorList(cAttrName, value, status)
 		if err := status.Err(); err != nil {
 			return fmt.Errorf("bad value for attribute %q: %w", name, err)
 		}
@@ -478,31 +606,46 @@ func setAttr(cdesc *C.TF_OperationDescription, status *status, name string, valu
 			}
 			dimsp = &dims[0]
 		}
-		C.TF_SetAttrShape(cdesc, cAttrName, dimsp, ndims)
+		setter.setShape(cAttrName, dimsp, ndims, status)
+		if err := status.Err(); err != nil {
+			return fmt.Errorf("bad value for attribute %q: %w", name, err)
+		}
 	case []Shape:
-		if len(value) == 0 {
-			C.TF_SetAttrShapeList(cdesc, cAttrName, nil, nil, 0)
-		} else {
-			var flatDims []C.int64_t
-			ndims := make([]C.int, len(value))
-			for i, s := range value {
-				nd := s.NumDimensions()
-				ndims[i] = C.int(nd)
-				for _, d := range s.dims {
-					flatDims = append(flatDims, C.int64_t(d))
-				}
-			}
-			var flatDimsp *C.int64_t
-			if len(flatDims) > 0 {
-				flatDimsp = &flatDims[0]
+		var flatDims []C.int64_t
+		ndims := make([]C.int, len(value))
+		for i, s := range value {
+			nd := s.NumDimensions()
+			ndims[i] = C.int(nd)
+			for _, d := range s.dims {
+				flatDims = append(flatDims, C.int64_t(d))
 			}
-			C.TF_SetAttrShapeList_Helper(cdesc, cAttrName, flatDimsp, &ndims[0], C.int(len(value)))
+		}
+		var flatDimsp *C.int64_t
+		if len(flatDims) > 0 {
+			flatDimsp = &flatDims[0]
+		}
+		var ndimsp *C.int
+		if len(ndims) > 0 {
+			ndimsp = &ndims[0]
+		}
+		setter.setShapeList(cAttrName, flatDimsp, ndimsp, C.int(len(value)), status)
+		if err := status.Err(); err != nil {
+			return fmt.Errorf("bad value for attribute %q: %w", name, err)
 		}
 	case *Func:
 		funcName := value.Name()
 		cstr := C.CString(funcName)
-		C.TF_SetAttrFuncName(cdesc, cAttrName, (*C.char)(unsafe.Pointer(cstr)), C.size_t(len(funcName)))
+		setter.setFuncName(cAttrName, (*C.char)(unsafe.Pointer(cstr)), C.size_t(len(funcName)))
 		C.free(unsafe.Pointer(cstr))
+	case *pbs.AttrValue:
+		proto := pbs.MustMarshal(value)
+		if len(proto) == 0 {
+			proto = []byte{0}
+		}
+		setter.setValueProto(cAttrName, unsafe.Pointer(&proto[0]), C.size_t(len(proto)), status)
+		if err := status.Err(); err != nil {
+			return fmt.Errorf("bad value for attribute %q: %w", name, err)
+		}
 	default:
 		return fmt.Errorf("attribute %q has a type (%T) which is not valid for operation attributes", name, value)
 	}
@@ -0,0 +1,609 @@
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package npy reads and writes tensors in the NumPy .npy and .npz file formats.
+//
+// The formats are described in
+// https://numpy.org/doc/stable/reference/generated/numpy.lib.format.html
+//
+// Arrays with a boolean, integer, floating point or complex dtype map to the
+// tensor of the corresponding [tf.DataType]. Arrays of fixed-width unicode
+// or byte strings map to [tf.String] tensors. Arrays stored in Fortran order
+// or in a byte order other than the one of the host are converted while
+// reading. Tensors are always written in C order and little-endian byte order.
+package npy
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+	"unsafe"
+
+	tf "github.com/hdu-hh/tensorflow/tensorflow/go"
+)
+
+// magic starts every .npy file.
+const magic = "\x93NUMPY"
+
+// headerAlignment is the alignment of the array data in a .npy file.
+const headerAlignment = 64
+
+// maxHeaderLen limits the length of the headers to read like NumPy does.
+const maxHeaderLen = 10000
+
+// allocChunk is the largest amount of array data that is allocated before
+// it has been read, so that a header with a huge shape cannot exhaust the
+// memory without the data being present.
+const allocChunk = 1 << 26
+
+// hostBigEndian is true if the host stores tensors in big-endian byte order.
+var hostBigEndian = func() bool {
+	x := uint16(1)
+	return *(*byte)(unsafe.Pointer(&x)) == 0
+}()
+
+// numericTypes maps the kind and size of a NumPy dtype to a tensor data type.
+var numericTypes = map[string]tf.DataType{
+	"b1":  tf.Bool,
+	"i1":  tf.Int8,
+	"i2":  tf.Int16,
+	"i4":  tf.Int32,
+	"i8":  tf.Int64,
+	"u1":  tf.Uint8,
+	"u2":  tf.Uint16,
+	"u4":  tf.Uint32,
+	"u8":  tf.Uint64,
+	"f2":  tf.Half,
+	"f4":  tf.Float,
+	"f8":  tf.Double,
+	"c8":  tf.Complex64,
+	"c16": tf.Complex128,
+}
+
+// header holds the array description stored in a .npy file.
+type header struct {
+	descr        string
+	fortranOrder bool
+	shape        []int64
+}
+
+// Read reads a tensor in the .npy format from r.
+func Read(r io.Reader) (*tf.Tensor, error) {
+	h, err := readHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	order, kind := h.descr[0], h.descr[1:]
+	if !strings.ContainsRune("<>|=", rune(order)) {
+		order, kind = '=', h.descr
+	}
+	if kind == "?" {
+		kind = "b1"
+	}
+	bigEndian := order == '>' || order == '=' && hostBigEndian
+	if dt, ok := numericTypes[kind]; ok {
+		size, _ := strconv.Atoi(kind[1:])
+		data, err := readData(r, h.shape, size)
+		if err != nil {
+			return nil, err
+		}
+		if bigEndian != hostBigEndian {
+			swapElements(data, kind)
+		}
+		if h.fortranOrder {
+			data = fortranToC(data, h.shape, size)
+		}
+		return tf.ReadTensor(dt, h.shape, bytes.NewReader(data))
+	}
+	if len(kind) > 1 && (kind[0] == 'U' || kind[0] == 'S') {
+		// NumPy has no empty string dtypes, which would also allow
+		// huge numbers of elements without any data
+		width, err := strconv.Atoi(kind[1:])
+		if err != nil || width <= 0 || width > math.MaxInt32/4 {
+			return nil, fmt.Errorf("invalid string dtype %q", h.descr)
+		}
+		size := width
+		if kind[0] == 'U' {
+			size *= 4
+		}
+		data, err := readData(r, h.shape, size)
+		if err != nil {
+			return nil, err
+		}
+		strs := make([]string, len(data)/size)
+		for i := range strs {
+			strs[i] = decodeString(data[i*size:(i+1)*size], kind[0], bigEndian)
+		}
+		if h.fortranOrder {
+			strs = fortranToCStrings(strs, h.shape)
+		}
+		return newStringTensor(strs, h.shape)
+	}
+	return nil, fmt.Errorf("unsupported dtype %q", h.descr)
+}
+
+// ReadFile reads a tensor from the .npy file with the given name.
+func ReadFile(name string) (*tf.Tensor, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	t, err := Read(bufio.NewReader(f))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %q: %w", name, err)
+	}
+	return t, nil
+}
+
+// Write writes the tensor t in the .npy format to w.
+func Write(w io.Writer, t *tf.Tensor) error {
+	dt := t.DataType()
+	if dt == tf.String {
+		return writeStrings(w, t)
+	}
+	var descr, kind string
+	for k, kindType := range numericTypes {
+		if kindType == dt {
+			kind, descr = k, "<"+k
+			if k[1:] == "1" {
+				descr = "|" + k
+			}
+		}
+	}
+	if descr == "" {
+		return fmt.Errorf("tensors of type %v cannot be stored in the .npy format", dt)
+	}
+	if err := writeHeader(w, header{descr: descr, shape: t.Shape()}); err != nil {
+		return err
+	}
+	if !hostBigEndian {
+		_, err := t.WriteContentsTo(w)
+		return err
+	}
+	var buf bytes.Buffer
+	if _, err := t.WriteContentsTo(&buf); err != nil {
+		return err
+	}
+	swapElements(buf.Bytes(), kind)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// WriteFile writes the tensor t into the .npy file with the given name.
+func WriteFile(name string, t *tf.Tensor) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(f)
+	err = Write(bw, t)
+	if err == nil {
+		err = bw.Flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// readHeader reads the magic string, the format version and the header.
+func readHeader(r io.Reader) (header, error) {
+	var h header
+	prefix := make([]byte, len(magic)+2)
+	if _, err := io.ReadFull(r, prefix); err != nil {
+		return h, fmt.Errorf("failed to read the .npy prefix: %w", err)
+	}
+	if string(prefix[:len(magic)]) != magic {
+		return h, errors.New("not a .npy file")
+	}
+	var headerLen int
+	switch major := prefix[len(magic)]; major {
+	case 1:
+		var l uint16
+		if err := binary.Read(r, binary.LittleEndian, &l); err != nil {
+			return h, err
+		}
+		headerLen = int(l)
+	case 2, 3:
+		// version 3 differs from version 2 only by the utf8 encoding of the header
+		var l uint32
+		if err := binary.Read(r, binary.LittleEndian, &l); err != nil {
+			return h, err
+		}
+		headerLen = int(l)
+		if headerLen > maxHeaderLen {
+			return h, fmt.Errorf("the .npy header of %d bytes exceeds %d bytes", headerLen, maxHeaderLen)
+		}
+	default:
+		return h, fmt.Errorf("unsupported .npy format version %d.%d", major, prefix[len(magic)+1])
+	}
+	b := make([]byte, headerLen)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return h, fmt.Errorf("failed to read the .npy header: %w", err)
+	}
+	dict, err := parseDict(string(b))
+	if err != nil {
+		return h, fmt.Errorf("invalid .npy header %q: %w", b, err)
+	}
+	var ok bool
+	if h.descr, ok = dict["descr"].(string); !ok || h.descr == "" {
+		return h, fmt.Errorf("unsupported dtype %v in .npy header", dict["descr"])
+	}
+	if h.fortranOrder, ok = dict["fortran_order"].(bool); !ok {
+		return h, fmt.Errorf("invalid fortran_order %v in .npy header", dict["fortran_order"])
+	}
+	if h.shape, ok = dict["shape"].([]int64); !ok {
+		return h, fmt.Errorf("invalid shape %v in .npy header", dict["shape"])
+	}
+	for _, d := range h.shape {
+		if d < 0 {
+			return h, fmt.Errorf("invalid shape %v in .npy header", h.shape)
+		}
+	}
+	return h, nil
+}
+
+// writeHeader writes the magic string, the format version and the header
+// padded to the alignment of the array data.
+func writeHeader(w io.Writer, h header) error {
+	dims := make([]string, len(h.shape))
+	for i, d := range h.shape {
+		dims[i] = strconv.FormatInt(d, 10)
+	}
+	shape := strings.Join(dims, ", ")
+	if len(dims) == 1 {
+		shape += ","
+	}
+	fortranOrder := "False"
+	if h.fortranOrder {
+		fortranOrder = "True"
+	}
+	dict := fmt.Sprintf("{'descr': '%s', 'fortran_order': %s, 'shape': (%s), }", h.descr, fortranOrder, shape)
+
+	// the header is padded with spaces and terminated by a newline, version 1
+	// is used unless its header length field is too small
+	version := []byte{1, 0}
+	padded := padHeader(dict, len(magic)+2+2)
+	if len(padded) > 0xffff {
+		version = []byte{2, 0}
+		padded = padHeader(dict, len(magic)+2+4)
+	}
+	dict = padded
+
+	buf := bytes.NewBufferString(magic)
+	buf.Write(version)
+	if version[0] == 1 {
+		binary.Write(buf, binary.LittleEndian, uint16(len(dict)))
+	} else {
+		binary.Write(buf, binary.LittleEndian, uint32(len(dict)))
+	}
+	buf.WriteString(dict)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// padHeader aligns the end of the header which follows a prefix of the
+// given length.
+func padHeader(dict string, prefixLen int) string {
+	pad := (headerAlignment - (prefixLen+len(dict)+1)%headerAlignment) % headerAlignment
+	return dict + strings.Repeat(" ", pad) + "\n"
+}
+
+// parseDict parses the Python dictionary literal of a .npy header.
+// It supports string, boolean, integer and tuple of integer values.
+func parseDict(s string) (map[string]interface{}, error) {
+	p := &dictParser{s: s}
+	dict := make(map[string]interface{})
+	if !p.consume('{') {
+		return nil, errors.New("missing '{'")
+	}
+	for !p.consume('}') {
+		key, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume(':') {
+			return nil, fmt.Errorf("missing ':' after key %q", key)
+		}
+		if dict[key], err = p.parseValue(); err != nil {
+			return nil, fmt.Errorf("invalid value of %q: %w", key, err)
+		}
+		if !p.consume(',') && p.peek() != '}' {
+			return nil, fmt.Errorf("missing ',' after value of %q", key)
+		}
+	}
+	return dict, nil
+}
+
+type dictParser struct {
+	s string
+}
+
+func (p *dictParser) peek() byte {
+	p.s = strings.TrimLeft(p.s, " \t\r\n")
+	if p.s == "" {
+		return 0
+	}
+	return p.s[0]
+}
+
+func (p *dictParser) consume(c byte) bool {
+	if p.peek() != c {
+		return false
+	}
+	p.s = p.s[1:]
+	return true
+}
+
+func (p *dictParser) parseValue() (interface{}, error) {
+	switch c := p.peek(); {
+	case c == '\'' || c == '"':
+		return p.parseString()
+	case c == '(':
+		p.consume('(')
+		dims := []int64{}
+		for !p.consume(')') {
+			d, err := p.parseInt()
+			if err != nil {
+				return nil, err
+			}
+			dims = append(dims, d)
+			if !p.consume(',') && p.peek() != ')' {
+				return nil, errors.New("missing ',' in tuple")
+			}
+		}
+		return dims, nil
+	case strings.HasPrefix(p.s, "True"):
+		p.s = p.s[len("True"):]
+		return true, nil
+	case strings.HasPrefix(p.s, "False"):
+		p.s = p.s[len("False"):]
+		return false, nil
+	default:
+		return p.parseInt()
+	}
+}
+
+func (p *dictParser) parseString() (string, error) {
+	quote := p.peek()
+	if quote != '\'' && quote != '"' {
+		return "", errors.New("missing string")
+	}
+	end := strings.IndexByte(p.s[1:], quote)
+	if end < 0 {
+		return "", errors.New("unterminated string")
+	}
+	str := p.s[1 : end+1]
+	p.s = p.s[end+2:]
+	return str, nil
+}
+
+func (p *dictParser) parseInt() (int64, error) {
+	p.peek()
+	end := strings.IndexFunc(p.s, func(r rune) bool { return (r < '0' || r > '9') && r != '-' })
+	if end < 0 {
+		end = len(p.s)
+	}
+	d, err := strconv.ParseInt(p.s[:end], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	// Python 2 wrote long integers with a suffix
+	p.s = strings.TrimPrefix(p.s[end:], "L")
+	return d, nil
+}
+
+// readData reads the array data of the shape with elements of the given
+// size. It fails if the size of the data overflows or if r ends before all
+// data has been read.
+func readData(r io.Reader, shape []int64, size int) ([]byte, error) {
+	n := int64(size)
+	for _, d := range shape {
+		if d == 0 {
+			return []byte{}, nil
+		}
+	}
+	for _, d := range shape {
+		if n > math.MaxInt/d {
+			return nil, fmt.Errorf("array of shape %v with elements of %d bytes is too large", shape, size)
+		}
+		n *= d
+	}
+	// the buffer grows with the data that has been read
+	var buf bytes.Buffer
+	if n <= allocChunk {
+		buf.Grow(int(n))
+	} else {
+		buf.Grow(allocChunk)
+	}
+	if _, err := buf.ReadFrom(io.LimitReader(r, n)); err != nil {
+		return nil, fmt.Errorf("failed to read %d bytes of array data: %w", n, err)
+	}
+	if int64(buf.Len()) != n {
+		return nil, fmt.Errorf("failed to read %d bytes of array data: %w", n, io.ErrUnexpectedEOF)
+	}
+	return buf.Bytes(), nil
+}
+
+// swapElements reverses the byte order of the elements of the numeric kind.
+// The real and imaginary parts of complex numbers are swapped separately.
+func swapElements(data []byte, kind string) {
+	size, _ := strconv.Atoi(kind[1:])
+	if kind[0] == 'c' {
+		size /= 2
+	}
+	swapBytes(data, size)
+}
+
+// swapBytes reverses the byte order of each element of the given size.
+func swapBytes(data []byte, size int) {
+	for i := 0; i+size <= len(data); i += size {
+		for j, k := i, i+size-1; j < k; j, k = j+1, k-1 {
+			data[j], data[k] = data[k], data[j]
+		}
+	}
+}
+
+// fortranIndices returns the position in Fortran order of each element
+// in C order.
+func fortranIndices(shape []int64) []int {
+	n := numElements(shape)
+	strides := make([]int, len(shape))
+	stride := 1
+	for i, d := range shape {
+		strides[i] = stride
+		stride *= int(d)
+	}
+	indices := make([]int, n)
+	index := make([]int64, len(shape))
+	pos := 0
+	for i := range indices {
+		indices[i] = pos
+		for d := len(shape) - 1; d >= 0; d-- {
+			index[d]++
+			pos += strides[d]
+			if index[d] < shape[d] {
+				break
+			}
+			pos -= strides[d] * int(shape[d])
+			index[d] = 0
+		}
+	}
+	return indices
+}
+
+// fortranToC reorders data with elements of the given size from Fortran
+// order into C order.
+func fortranToC(data []byte, shape []int64, size int) []byte {
+	c := make([]byte, len(data))
+	for i, f := range fortranIndices(shape) {
+		copy(c[i*size:(i+1)*size], data[f*size:(f+1)*size])
+	}
+	return c
+}
+
+func fortranToCStrings(strs []string, shape []int64) []string {
+	c := make([]string, len(strs))
+	for i, f := range fortranIndices(shape) {
+		c[i] = strs[f]
+	}
+	return c
+}
+
+// decodeString decodes a fixed-width NumPy string without its trailing zeros.
+// Unicode strings of kind 'U' are stored as UTF-32, byte strings of kind 'S'
+// as is.
+func decodeString(b []byte, kind byte, bigEndian bool) string {
+	if kind == 'S' {
+		return string(bytes.TrimRight(b, "\x00"))
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	if bigEndian {
+		order = binary.BigEndian
+	}
+	var sb strings.Builder
+	for i := 0; i+4 <= len(b); i += 4 {
+		r := order.Uint32(b[i:])
+		if r == 0 {
+			break
+		}
+		sb.WriteRune(rune(r))
+	}
+	return sb.String()
+}
+
+// writeStrings writes a string tensor as unicode strings or, if some strings
+// are no valid UTF-8, as byte strings.
+func writeStrings(w io.Writer, t *tf.Tensor) error {
+	strs := flattenStrings(reflect.ValueOf(t.Value()), nil)
+	kind, width := "<U", 1
+	for _, s := range strs {
+		if !utf8.ValidString(s) {
+			kind = "|S"
+			break
+		}
+	}
+	for _, s := range strs {
+		l := len(s)
+		if kind == "<U" {
+			l = utf8.RuneCountInString(s)
+		}
+		if l > width {
+			width = l
+		}
+	}
+	h := header{descr: kind + strconv.Itoa(width), shape: t.Shape()}
+	if err := writeHeader(w, h); err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	for _, s := range strs {
+		if kind == "|S" {
+			bw.WriteString(s)
+			bw.Write(make([]byte, width-len(s)))
+			continue
+		}
+		n := 0
+		for _, r := range s {
+			binary.Write(bw, binary.LittleEndian, uint32(r))
+			n++
+		}
+		bw.Write(make([]byte, 4*(width-n)))
+	}
+	return bw.Flush()
+}
+
+func flattenStrings(v reflect.Value, strs []string) []string {
+	if v.Kind() == reflect.String {
+		return append(strs, v.String())
+	}
+	for i := 0; i < v.Len(); i++ {
+		strs = flattenStrings(v.Index(i), strs)
+	}
+	return strs
+}
+
+func newStringTensor(strs []string, shape []int64) (*tf.Tensor, error) {
+	if len(shape) == 0 {
+		return tf.NewTensor(strs[0])
+	}
+	t, err := tf.NewTensor(strs)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.Reshape(shape); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func numElements(shape []int64) int {
+	n := 1
+	for _, d := range shape {
+		n *= int(d)
+	}
+	return n
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package npy
+
+import (
+	"bytes"
+	"encoding/binary"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	tf "github.com/hdu-hh/tensorflow/tensorflow/go"
+)
+
+// npyFile builds a .npy file of the given version like NumPy would.
+func npyFile(major byte, dict string, data []byte) []byte {
+	prefixLen := len(magic) + 2 + 2
+	if major > 1 {
+		prefixLen += 2
+	}
+	dict = padHeader(dict, prefixLen)
+	buf := bytes.NewBufferString(magic)
+	buf.Write([]byte{major, 0})
+	if major == 1 {
+		binary.Write(buf, binary.LittleEndian, uint16(len(dict)))
+	} else {
+		binary.Write(buf, binary.LittleEndian, uint32(len(dict)))
+	}
+	buf.WriteString(dict)
+	buf.Write(data)
+	return buf.Bytes()
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		file []byte
+		want interface{}
+	}{
+		{
+			npyFile(1, "{'descr': '<f4', 'fortran_order': False, 'shape': (2,), }", []byte{0, 0, 0x80, 0x3f, 0, 0, 0, 0xc0}),
+			[]float32{1, -2},
+		},
+		{
+			npyFile(1, "{'descr': '>i4', 'fortran_order': False, 'shape': (2,), }", []byte{0, 0, 0, 1, 0xff, 0xff, 0xff, 0xfe}),
+			[]int32{1, -2},
+		},
+		{
+			// Fortran order stores the columns one after another
+			npyFile(2, "{'descr': '|u1', 'fortran_order': True, 'shape': (2, 3), }", []byte{1, 4, 2, 5, 3, 6}),
+			[][]uint8{{1, 2, 3}, {4, 5, 6}},
+		},
+		{
+			npyFile(3, "{'descr': '|b1', 'fortran_order': False, 'shape': (), }", []byte{1}),
+			true,
+		},
+		{
+			npyFile(1, "{'descr': '>c8', 'fortran_order': False, 'shape': (1,), }", []byte{0x3f, 0x80, 0, 0, 0x40, 0, 0, 0}),
+			[]complex64{1 + 2i},
+		},
+		{
+			// the parts of complex numbers are swapped before reordering them
+			npyFile(1, "{'descr': '>c8', 'fortran_order': True, 'shape': (2, 2), }", []byte{
+				0x3f, 0x80, 0, 0, 0, 0, 0, 0,
+				0x40, 0x40, 0, 0, 0, 0, 0, 0,
+				0, 0, 0, 0, 0x40, 0, 0, 0,
+				0, 0, 0, 0, 0x40, 0x80, 0, 0,
+			}),
+			[][]complex64{{1, 2i}, {3, 4i}},
+		},
+		{
+			npyFile(1, "{'descr': '<f2', 'fortran_order': False, 'shape': (1, 1), }", []byte{0x00, 0x3c}),
+			[][]tf.Float16{{tf.NewFloat16(1)}},
+		},
+		{
+			npyFile(1, "{'descr': '<U3', 'fortran_order': False, 'shape': (2,), }", []byte{
+				'a', 0, 0, 0, 0xe4, 0, 0, 0, 0, 0, 0, 0,
+				'x', 0, 0, 0, 'y', 0, 0, 0, 'z', 0, 0, 0,
+			}),
+			[]string{"aä", "xyz"},
+		},
+		{
+			npyFile(1, "{'descr': '|S2', 'fortran_order': True, 'shape': (2, 2), }", []byte("a\x00c\x00bbdd")),
+			[][]string{{"a", "bb"}, {"c", "dd"}},
+		},
+		{
+			npyFile(1, "{'descr': '<i8', 'fortran_order': False, 'shape': (0, 2), }", nil),
+			[][]int64{},
+		},
+	}
+	for _, test := range tests {
+		tensor, err := Read(bytes.NewReader(test.file))
+		if err != nil {
+			t.Errorf("Read(%q): %v", test.file, err)
+			continue
+		}
+		if got := tensor.Value(); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Read(%q) = %#v, want %#v", test.file, got, test.want)
+		}
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	tests := [][]byte{
+		[]byte("not an npy file"),
+		npyFile(4, "{'descr': '<f4', 'fortran_order': False, 'shape': (), }", []byte{0, 0, 0, 0}),
+		npyFile(1, "{'descr': '<f4', 'fortran_order': False, 'shape': (2,), }", []byte{0, 0, 0, 0}),
+		npyFile(1, "{'descr': '<f4', 'shape': (), }", []byte{0, 0, 0, 0}),
+		npyFile(1, "{'descr': [('x', '<f4')], 'fortran_order': False, 'shape': (), }", []byte{0, 0, 0, 0}),
+		npyFile(1, "{'descr': '<M8', 'fortran_order': False, 'shape': (), }", make([]byte, 8)),
+		// crafted headers must neither overflow nor allocate missing data
+		npyFile(1, "{'descr': '<f4', 'fortran_order': False, 'shape': (-1,), }", []byte{0, 0, 0, 0}),
+		npyFile(1, "{'descr': '<f8', 'fortran_order': False, 'shape': (4294967296, 4294967296), }", make([]byte, 8)),
+		npyFile(1, "{'descr': '<f4', 'fortran_order': False, 'shape': (1099511627776,), }", []byte{0, 0, 0, 0}),
+		npyFile(1, "{'descr': '|S0', 'fortran_order': False, 'shape': (1099511627776,), }", nil),
+		npyFile(1, "{'descr': '<U4294967295', 'fortran_order': False, 'shape': (1,), }", nil),
+		npyFile(2, "{'descr': '<f4', 'fortran_order': False, 'shape': (), }"+strings.Repeat(" ", maxHeaderLen), []byte{0, 0, 0, 0}),
+	}
+	for _, file := range tests {
+		if _, err := Read(bytes.NewReader(file)); err == nil {
+			t.Errorf("Read(%q) did not fail", file)
+		}
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	values := []interface{}{
+		int8(-3),
+		[]uint16{1, 2},
+		[][]float64{{1, 2}, {3, 4}, {5, 6}},
+		[]complex128{1 - 1i},
+		[]bool{true, false, true},
+		[]tf.Float16{tf.NewFloat16(-1.5)},
+		"scalar",
+		[][]string{{"ä", ""}, {"longer", "x"}},
+		[]string{"\xff\x00binary"},
+	}
+	for _, value := range values {
+		tensor, err := tf.NewTensor(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := Write(&buf, tensor); err != nil {
+			t.Errorf("Write(%v): %v", value, err)
+			continue
+		}
+		if buf.Len() < 64 || bytes.IndexByte(buf.Bytes(), '\n')%64 != 63 {
+			t.Errorf("Write(%v) did not align the data: %q", value, buf.Bytes())
+		}
+		got, err := Read(&buf)
+		if err != nil {
+			t.Errorf("Read(Write(%v)): %v", value, err)
+			continue
+		}
+		if !reflect.DeepEqual(got.Value(), value) {
+			t.Errorf("Read(Write(%v)) = %v", value, got.Value())
+		}
+	}
+}
+
+func TestNpz(t *testing.T) {
+	x, err := tf.NewTensor([][]float32{{1, 2}, {3, 4}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	y, err := tf.NewTensor([]string{"a", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := filepath.Join(t.TempDir(), "tensors.npz")
+	if err := WriteNpzFile(name, map[string]*tf.Tensor{"x": x, "y": y}); err != nil {
+		t.Fatal(err)
+	}
+	tensors, err := ReadNpzFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tensors) != 2 {
+		t.Fatalf("Got %d tensors, want 2", len(tensors))
+	}
+	if got := tensors["x"].Value(); !reflect.DeepEqual(got, x.Value()) {
+		t.Errorf("Got %v, want %v", got, x.Value())
+	}
+	if got := tensors["y"].Value(); !reflect.DeepEqual(got, y.Value()) {
+		t.Errorf("Got %v, want %v", got, y.Value())
+	}
+}
+
+func TestParseDict(t *testing.T) {
+	dict, err := parseDict(`{"descr": "<f8", 'fortran_order': False, 'shape': (3L, 4L)}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"descr": "<f8", "fortran_order": false, "shape": []int64{3, 4}}
+	if !reflect.DeepEqual(dict, want) {
+		t.Errorf("Got %v, want %v", dict, want)
+	}
+}
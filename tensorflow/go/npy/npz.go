@@ -0,0 +1,114 @@
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package npy
+
+import (
+	"archive/zip"
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	tf "github.com/hdu-hh/tensorflow/tensorflow/go"
+)
+
+// ReadNpz reads the tensors of an .npz archive with the given size from r.
+// The tensors are keyed by the names of the arrays, i.e. the names of the
+// archived .npy files without their extension. The archive may be compressed.
+func ReadNpz(r io.ReaderAt, size int64) (map[string]*tf.Tensor, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	tensors := make(map[string]*tf.Tensor, len(zr.File))
+	for _, f := range zr.File {
+		t, err := readNpzEntry(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %q: %w", f.Name, err)
+		}
+		tensors[strings.TrimSuffix(f.Name, ".npy")] = t
+	}
+	return tensors, nil
+}
+
+func readNpzEntry(f *zip.File) (*tf.Tensor, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return Read(bufio.NewReader(rc))
+}
+
+// ReadNpzFile reads the tensors of the .npz file with the given name.
+func ReadNpzFile(name string) (map[string]*tf.Tensor, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	tensors, err := ReadNpz(f, info.Size())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %q: %w", name, err)
+	}
+	return tensors, nil
+}
+
+// WriteNpz writes the tensors as an uncompressed .npz archive to w
+// like numpy.savez does. The arrays are named by the keys of the map.
+func WriteNpz(w io.Writer, tensors map[string]*tf.Tensor) error {
+	names := make([]string, 0, len(tensors))
+	for name := range tensors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	zw := zip.NewWriter(w)
+	for _, name := range names {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: name + ".npy", Method: zip.Store})
+		if err != nil {
+			return err
+		}
+		if err := Write(fw, tensors[name]); err != nil {
+			return fmt.Errorf("failed to write %q: %w", name, err)
+		}
+	}
+	return zw.Close()
+}
+
+// WriteNpzFile writes the tensors into the .npz file with the given name.
+func WriteNpzFile(name string, tensors map[string]*tf.Tensor) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(f)
+	err = WriteNpz(bw, tensors)
+	if err == nil {
+		err = bw.Flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,194 @@
+package op
+
+import (
+	"fmt"
+	"math"
+
+	tf "github.com/hdu-hh/tensorflow/tensorflow/go"
+)
+
+// MultiHeadAttention implements the scaled dot-product attention of multiple
+// heads (see https://arxiv.org/abs/1706.03762) for queries q of the shape
+// [batch, queries, features] and keys k and values v of the shape
+// [batch, keys, features]. The features are split evenly among the heads.
+//
+// The mask, if mask.Op is not nil, has 1 for the keys to attend to and 0 for
+// the ones to ignore. It must be broadcastable to the shape of the attention
+// weights [batch, heads, queries, keys], like the masks returned by
+// [CausalMask] and [KeyPaddingMask] or their product.
+//
+// The projections of the queries, keys, values and outputs are like [Linear]
+// and get tagged with the tags.
+func MultiHeadAttention(s *Scope, q, k, v tf.Output, heads int, mask tf.Output, tags ...VarTag) tf.Output {
+	return multiHeadAttention(s, q, k, v, heads, mask, false, tags)
+}
+
+// multiHeadAttention implements MultiHeadAttention, which rotates the
+// projected queries and keys by their positions if rotary is set.
+func multiHeadAttention(s *Scope, q, k, v tf.Output, heads int, mask tf.Output, rotary bool, tags []VarTag) tf.Output {
+	features := q.Shape().Size(-1)
+	if features <= 0 || heads <= 0 || features%int64(heads) != 0 {
+		s.UpdateErr("MultiHeadAttention", fmt.Errorf("%d features cannot be split among %d heads", features, heads))
+		return tf.Output{}
+	}
+	depth := features / int64(heads)
+	q = s.splitHeads(Linear(s, q, int(features), tags...), heads)
+	k = s.splitHeads(Linear(s, k, int(features), tags...), heads)
+	v = s.splitHeads(Linear(s, v, int(features), tags...), heads)
+	if rotary {
+		q, k = RotaryEncoding(s, q), RotaryEncoding(s, k)
+	}
+
+	scores := BatchMatMulV3(s, q, k, q.DataType(), BatchMatMulV3AdjY(true))
+	scores = Mul(s, scores, Cast(s, Const(s, float32(1/math.Sqrt(float64(depth)))), q.DataType()))
+	if mask.Op != nil { // push the scores of ignored keys towards -inf
+		ignored := Sub(s, OnesLike(s, mask), mask)
+		scores = Add(s, scores, Mul(s, ignored, Cast(s, Const(s, float32(-1e9)), q.DataType())))
+	}
+	weights := Softmax(s, scores)
+	y := BatchMatMulV3(s, weights, v, v.DataType())
+	return Linear(s, s.mergeHeads(y, features), int(features), tags...)
+}
+
+// splitHeads reshapes x from [batch, time, heads*depth] to
+// [batch, heads, time, depth].
+func (s *Scope) splitHeads(x tf.Output, heads int) tf.Output {
+	depth := x.Shape().Size(-1) / int64(heads)
+	shape := ConcatV2(s, []tf.Output{s.dynamicDims(x, 0, 2), Const(s, []int32{int32(heads), int32(depth)})}, Const(s, int32(0)))
+	return Transpose(s, Reshape(s, x, shape), Const(s, []int32{0, 2, 1, 3}))
+}
+
+// mergeHeads reshapes x from [batch, heads, time, depth] to
+// [batch, time, features].
+func (s *Scope) mergeHeads(x tf.Output, features int64) tf.Output {
+	x = Transpose(s, x, Const(s, []int32{0, 2, 1, 3}))
+	shape := ConcatV2(s, []tf.Output{s.dynamicDims(x, 0, 2), Const(s, []int32{int32(features)})}, Const(s, int32(0)))
+	return Reshape(s, x, shape)
+}
+
+// CausalMask returns the mask of the shape [length, length] for
+// [MultiHeadAttention] which lets each position attend only to itself and
+// the preceding positions.
+func CausalMask(s *Scope, length int) tf.Output {
+	mask := make([][]float32, length)
+	for i := range mask {
+		mask[i] = make([]float32, length)
+		for j := 0; j <= i; j++ {
+			mask[i][j] = 1
+		}
+	}
+	return Const(s, mask)
+}
+
+// KeyPaddingMask returns the mask for [MultiHeadAttention] of the shape
+// [batch, 1, 1, keys] for the valid keys of the shape [batch, keys], which
+// has 1 for the valid keys and 0 for the padding.
+func KeyPaddingMask(s *Scope, valid tf.Output) tf.Output {
+	return Reshape(s, valid, ConcatV2(s, []tf.Output{s.dynamicDims(valid, 0, 1), Const(s, []int32{1, 1, -1})}, Const(s, int32(0))))
+}
+
+// SinusoidalEncoding adds the sinusoidal encodings of the positions to x of
+// the shape [batch, time, features] with a known time and an even number of
+// features, i.e. sin(p/10000^(2i/features)) for the feature 2i and
+// cos(p/10000^(2i/features)) for the feature 2i+1 at the position p.
+func SinusoidalEncoding(s *Scope, x tf.Output) tf.Output {
+	steps, features := x.Shape().Size(-2), x.Shape().Size(-1)
+	if steps <= 0 || features <= 0 || features%2 != 0 {
+		s.UpdateErr("SinusoidalEncoding", fmt.Errorf("need a known time and an even number of features, got %v", x.Shape()))
+		return tf.Output{}
+	}
+	encoding := make([][]float32, steps)
+	for p := range encoding {
+		encoding[p] = make([]float32, features)
+		for i := int64(0); i < features; i += 2 {
+			angle := float64(p) / math.Pow(10000, float64(i)/float64(features))
+			encoding[p][i] = float32(math.Sin(angle))
+			encoding[p][i+1] = float32(math.Cos(angle))
+		}
+	}
+	return Add(s, x, Cast(s, Const(s, encoding), x.DataType()))
+}
+
+// RotaryEncoding rotates pairs of features of x by angles proportional to
+// their positions (RoPE, see https://arxiv.org/abs/2104.09864), so that the
+// dot-products of rotated queries and keys depend on their relative
+// positions. x has the shape [..., time, features] with a known time and an
+// even number of features, whose first and second halves are paired.
+func RotaryEncoding(s *Scope, x tf.Output) tf.Output {
+	steps, features := x.Shape().Size(-2), x.Shape().Size(-1)
+	if steps <= 0 || features <= 0 || features%2 != 0 {
+		s.UpdateErr("RotaryEncoding", fmt.Errorf("need a known time and an even number of features, got %v", x.Shape()))
+		return tf.Output{}
+	}
+	half := features / 2
+	cos, sin := make([][]float32, steps), make([][]float32, steps)
+	for p := range cos {
+		cos[p], sin[p] = make([]float32, half), make([]float32, half)
+		for i := range cos[p] {
+			angle := float64(p) / math.Pow(10000, float64(i)/float64(half))
+			cos[p][i], sin[p][i] = float32(math.Cos(angle)), float32(math.Sin(angle))
+		}
+	}
+	cosC, sinC := Cast(s, Const(s, cos), x.DataType()), Cast(s, Const(s, sin), x.DataType())
+	axis := Const(s, int32(-1))
+	halves := Split(s, axis, x, 2)
+	x1, x2 := halves[0], halves[1]
+	return ConcatV2(s, []tf.Output{
+		Sub(s, Mul(s, x1, cosC), Mul(s, x2, sinC)),
+		Add(s, Mul(s, x1, sinC), Mul(s, x2, cosC)),
+	}, axis)
+}
+
+// TransformerOpts are the options of [TransformerEncoderBlock].
+type TransformerOpts struct {
+	// Mask is the attention mask like for [MultiHeadAttention],
+	// e.g. a [KeyPaddingMask]. It is ignored if Mask.Op is nil.
+	Mask tf.Output
+
+	// Causal lets each position attend only to itself and the preceding
+	// positions, e.g. for autoregressive decoding.
+	Causal bool
+
+	// Rotary applies the [RotaryEncoding] to the queries and keys of
+	// each head.
+	Rotary bool
+
+	// DropoutRate is the rate of the [Dropout] applied to the outputs of
+	// the attention and of the feed-forward network.
+	DropoutRate float32
+
+	// Tags of the kernels and biases like for [Linear] and [Bias].
+	Tags []VarTag
+}
+
+// TransformerEncoderBlock implements a Transformer encoder block for x of the
+// shape [batch, time, features] (see https://arxiv.org/abs/1706.03762).
+// It consists of self-attention and a feed-forward network with ffUnits
+// hidden units and the Gelu activation, each followed by dropout, a
+// residual connection and the [LayerNormalization] of each position, whose
+// scales and offsets are tagged like the kernels.
+func TransformerEncoderBlock(s *Scope, x tf.Output, heads, ffUnits int, opts TransformerOpts) tf.Output {
+	features := x.Shape().Size(-1)
+	mask := opts.Mask
+	if opts.Causal {
+		steps := x.Shape().Size(-2)
+		if steps <= 0 {
+			s.UpdateErr("TransformerEncoderBlock", fmt.Errorf("causal masks need a known time, got %v", x.Shape()))
+			return tf.Output{}
+		}
+		causal := CausalMask(s, int(steps))
+		if mask.Op != nil {
+			mask = Mul(s, mask, causal)
+		} else {
+			mask = causal
+		}
+	}
+	attention := multiHeadAttention(s.SubScope("attention"), x, x, x, heads, mask, opts.Rotary, opts.Tags)
+	norm := NormOpts{Tags: opts.Tags}
+	x = LayerNormalization(s, Add(s, x, Dropout(s, attention, opts.DropoutRate)), norm)
+
+	ff := s.SubScope("feedforward")
+	y := Gelu(ff, ff.channelBias(Linear(ff, x, ffUnits, opts.Tags...), int64(ffUnits), opts.Tags))
+	y = ff.channelBias(Linear(ff, y, int(features), opts.Tags...), features, opts.Tags)
+	return LayerNormalization(s, Add(s, x, Dropout(s, y, opts.DropoutRate)), norm)
+}
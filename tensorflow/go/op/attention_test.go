@@ -0,0 +1,169 @@
+package op
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	tf "github.com/hdu-hh/tensorflow/tensorflow/go"
+)
+
+func TestMultiHeadAttention(t *testing.T) {
+	const B, T, F = 2, 3, 8
+	s := NewScope()
+	x := Placeholder(s, tf.Float, PlaceholderShape(tf.MakeShape(B, T, F)))
+	valid := Placeholder(s, tf.Float, PlaceholderShape(tf.MakeShape(B, T)))
+	causal := MultiHeadAttention(s, x, x, x, 2, CausalMask(s, T))
+	padded := MultiHeadAttention(s, x, x, x, 4, KeyPaddingMask(s, valid))
+	block := TransformerEncoderBlock(s, SinusoidalEncoding(s, x), 2, 16, TransformerOpts{
+		Mask:        KeyPaddingMask(s, valid),
+		Causal:      true,
+		Rotary:      true,
+		DropoutRate: 0.1,
+	})
+	sess := newTestSession(t, s)
+
+	run := func(xValue [][][]float32) (causalY, paddedY, blockY [][][]float32) {
+		t.Helper()
+		fetched, err := sess.Run(map[tf.Output]*tf.Tensor{
+			x:     mustTensor(t, xValue),
+			valid: mustTensor(t, [][]float32{{1, 1, 1}, {1, 1, 0}}),
+		}, []tf.Output{causal, padded, block}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, f := range fetched {
+			if got := f.Shape(); !reflect.DeepEqual(got, []int64{B, T, F}) {
+				t.Errorf("Got shape %v, want %v", got, []int64{B, T, F})
+			}
+		}
+		return fetched[0].Value().([][][]float32), fetched[1].Value().([][][]float32), fetched[2].Value().([][][]float32)
+	}
+	xValue := make([][][]float32, B)
+	for b := range xValue {
+		xValue[b] = make([][]float32, T)
+		for i := range xValue[b] {
+			xValue[b][i] = make([]float32, F)
+			for j := range xValue[b][i] {
+				xValue[b][i][j] = float32(math.Sin(float64(b*T*F + i*F + j)))
+			}
+		}
+	}
+	causal1, padded1, _ := run(xValue)
+	// changing the last position must neither affect the preceding
+	// positions with causal masks nor the other positions if it is padding
+	for j := range xValue[1][T-1] {
+		xValue[1][T-1][j] += 1
+	}
+	causal2, padded2, _ := run(xValue)
+	for i := 0; i < T-1; i++ {
+		if !approxEqual(causal1[1][i], causal2[1][i]) {
+			t.Errorf("Causal output of position %d changed from %v to %v", i, causal1[1][i], causal2[1][i])
+		}
+		if !approxEqual(padded1[1][i], padded2[1][i]) {
+			t.Errorf("Padded output of position %d changed from %v to %v", i, padded1[1][i], padded2[1][i])
+		}
+	}
+	if approxEqual(causal1[1][T-1], causal2[1][T-1]) {
+		t.Errorf("Causal output of the changed position did not change")
+	}
+}
+
+func TestTransformerUnknownBatch(t *testing.T) {
+	const T, F = 3, 8
+	s := NewScope()
+	x := Placeholder(s, tf.Float, PlaceholderShape(tf.MakeShape(-1, T, F)))
+	y := TransformerEncoderBlock(s, x, 2, 16, TransformerOpts{Causal: true})
+	// both layer normalizations have a trainable scale and offset per feature
+	norms := 0
+	for _, param := range s.GetParams(TagTrainable) {
+		if reflect.DeepEqual(param.Shape(), tf.MakeShape(1, 1, F)) {
+			norms++
+		}
+	}
+	if norms != 4 {
+		t.Errorf("Got %d trainable normalization parameters, want 4", norms)
+	}
+	sess := newTestSession(t, s)
+	for _, batch := range []int{1, 3} {
+		xValue := make([][][]float32, batch)
+		for b := range xValue {
+			xValue[b] = make([][]float32, T)
+			for i := range xValue[b] {
+				xValue[b][i] = make([]float32, F)
+				for j := range xValue[b][i] {
+					xValue[b][i][j] = float32(b + i - j)
+				}
+			}
+		}
+		fetched, err := sess.Run(map[tf.Output]*tf.Tensor{x: mustTensor(t, xValue)}, []tf.Output{y}, nil)
+		if err != nil {
+			t.Fatalf("batch %d: %v", batch, err)
+		}
+		if got, want := fetched[0].Shape(), []int64{int64(batch), T, F}; !reflect.DeepEqual(got, want) {
+			t.Errorf("Got shape %v, want %v", got, want)
+		}
+	}
+}
+
+func TestPositionalEncodings(t *testing.T) {
+	s := NewScope()
+	x := Const(s, [][][]float32{{{1, 2, 3, 4}, {1, 2, 3, 4}, {1, 2, 3, 4}}})
+	sinusoidal := SinusoidalEncoding(s, ZerosLike(s, x))
+	rotary := RotaryEncoding(s, x)
+	sess := newTestSession(t, s)
+	fetched, err := sess.Run(nil, []tf.Output{sinusoidal, rotary}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc := fetched[0].Value().([][][]float32)[0]
+	if want := []float32{0, 1, 0, 1}; !approxEqual(enc[0], want) {
+		t.Errorf("Got encoding %v of position 0, want %v", enc[0], want)
+	}
+	if want := []float32{float32(math.Sin(1)), float32(math.Cos(1)), float32(math.Sin(0.01)), float32(math.Cos(0.01))}; !approxEqual(enc[1], want) {
+		t.Errorf("Got encoding %v of position 1, want %v", enc[1], want)
+	}
+	rot := fetched[1].Value().([][][]float32)[0]
+	if want := []float32{1, 2, 3, 4}; !approxEqual(rot[0], want) {
+		t.Errorf("Got rotation %v of position 0, want %v", rot[0], want)
+	}
+	for p, v := range rot {
+		// rotations keep the norms of the pairs (1, 3) and (2, 4)
+		if n := v[0]*v[0] + v[2]*v[2]; math.Abs(float64(n-10)) > 1e-4 {
+			t.Errorf("Got norm %v of the first pair at position %d, want 10", n, p)
+		}
+		if n := v[1]*v[1] + v[3]*v[3]; math.Abs(float64(n-20)) > 1e-4 {
+			t.Errorf("Got norm %v of the second pair at position %d, want 20", n, p)
+		}
+	}
+}
+
+func TestDropout(t *testing.T) {
+	s := NewScope()
+	x := Const(s, [][]float32{{1, 2, 3, 4}, {5, 6, 7, 8}})
+	if Dropout(s, x, 0) != x {
+		t.Errorf("Dropout with rate 0 changed the input")
+	}
+	y := Dropout(s, x, 0.5)
+	training := s.Training()
+	sess := newTestSession(t, s)
+	fetched, err := sess.Run(nil, []tf.Output{y}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fetched[0].Value(), [][]float32{{1, 2, 3, 4}, {5, 6, 7, 8}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %v outside of the training phase, want %v", got, want)
+	}
+	feeds := map[tf.Output]*tf.Tensor{training: mustTensor(t, true)}
+	fetched, err = sess.Run(feeds, []tf.Output{y}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, row := range fetched[0].Value().([][]float32) {
+		for j, v := range row {
+			if want := float32(2 * (4*i + j + 1)); v != 0 && v != want {
+				t.Errorf("Got %v at [%d, %d], want 0 or %v", v, i, j, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package op
+
+import (
+	"fmt"
+
+	tf "github.com/hdu-hh/tensorflow/tensorflow/go"
+)
+
+// Conv1DLayer implements a 1D convolution layer for inputs of the shape
+// [batch, width, channels], see [Conv2DLayer].
+func Conv1DLayer(s *Scope, x tf.Output, filters, kernel, stride int, padding string, tags ...VarTag) tf.Output {
+	inX := x.Shape().Size(-1)
+	w := s.kernelVariable(tf.MakeShape(int64(kernel), inX, int64(filters)), x.DataType(), tags)
+	// convolve as images of height 1
+	axis1 := Const(s, int32(1))
+	w = ExpandDims(s, w, Const(s, int32(0)))
+	y := Conv2D(s, ExpandDims(s, x, axis1), w, []int64{1, 1, int64(stride), 1}, padding)
+	y = Squeeze(s, y, SqueezeAxis([]int64{1}))
+	return s.channelBias(y, int64(filters), tags)
+}
+
+// Conv2DLayer implements a 2D convolution layer for inputs of the shape
+// [batch, height, width, channels] with square kernels. The padding is
+// either "SAME" or "VALID".
+//
+// Its kernel is trainable with L2-norm weight decay and gets initialized with
+// Xavier values by default, and its per-channel bias is like the one of
+// [Bias]. Use tags to select other behaviours for both.
+// (The "Layer" suffix distinguishes the convolution layers from the
+// convolution operations, like [Conv2D].)
+func Conv2DLayer(s *Scope, x tf.Output, filters, kernel, stride int, padding string, tags ...VarTag) tf.Output {
+	k, inX := int64(kernel), x.Shape().Size(-1)
+	w := s.kernelVariable(tf.MakeShape(k, k, inX, int64(filters)), x.DataType(), tags)
+	y := Conv2D(s, x, w, []int64{1, int64(stride), int64(stride), 1}, padding)
+	return s.channelBias(y, int64(filters), tags)
+}
+
+// Conv3DLayer implements a 3D convolution layer for inputs of the shape
+// [batch, depth, height, width, channels], see [Conv2DLayer].
+func Conv3DLayer(s *Scope, x tf.Output, filters, kernel, stride int, padding string, tags ...VarTag) tf.Output {
+	k, inX := int64(kernel), x.Shape().Size(-1)
+	w := s.kernelVariable(tf.MakeShape(k, k, k, inX, int64(filters)), x.DataType(), tags)
+	st := int64(stride)
+	y := Conv3D(s, x, w, []int64{1, st, st, st, 1}, padding)
+	return s.channelBias(y, int64(filters), tags)
+}
+
+// DepthwiseConv2DLayer implements a depthwise 2D convolution layer for inputs
+// of the shape [batch, height, width, channels], which convolves each input
+// channel separately into multiplier output channels, see [Conv2DLayer].
+func DepthwiseConv2DLayer(s *Scope, x tf.Output, multiplier, kernel, stride int, padding string, tags ...VarTag) tf.Output {
+	k, inX := int64(kernel), x.Shape().Size(-1)
+	w := s.kernelVariable(tf.MakeShape(k, k, inX, int64(multiplier)), x.DataType(), tags)
+	y := DepthwiseConv2dNative(s, x, w, []int64{1, int64(stride), int64(stride), 1}, padding)
+	return s.channelBias(y, inX*int64(multiplier), tags)
+}
+
+// Conv2DTransposeLayer implements a transposed 2D convolution layer, also
+// known as deconvolution, for inputs of the shape
+// [batch, height, width, channels]. It upsamples the height and width by
+// the stride, see [Conv2DLayer].
+func Conv2DTransposeLayer(s *Scope, x tf.Output, filters, kernel, stride int, padding string, tags ...VarTag) tf.Output {
+	k, st, inX := int64(kernel), int64(stride), x.Shape().Size(-1)
+	w := s.kernelVariable(tf.MakeShape(k, k, int64(filters), inX), x.DataType(), tags)
+	// the output size is size*stride, plus the overlap of kernels for VALID
+	var extra int32
+	if padding == "VALID" && k > st {
+		extra = int32(k - st)
+	}
+	inSizes := Shape(s, x, ShapeOutType(tf.Int32))
+	outSizes := Add(s,
+		Mul(s, inSizes, Const(s, []int32{1, int32(st), int32(st), 0})),
+		Const(s, []int32{0, extra, extra, int32(filters)}))
+	y := Conv2DBackpropInput(s, outSizes, w, x, []int64{1, st, st, 1}, padding)
+	return s.channelBias(y, int64(filters), tags)
+}
+
+// MaxPool2D implements a 2D max pooling layer for inputs of the shape
+// [batch, height, width, channels] with square windows.
+func MaxPool2D(s *Scope, x tf.Output, size, stride int, padding string) tf.Output {
+	sz, st := int64(size), int64(stride)
+	return MaxPool(s, x, []int64{1, sz, sz, 1}, []int64{1, st, st, 1}, padding)
+}
+
+// AvgPool2D implements a 2D average pooling layer for inputs of the shape
+// [batch, height, width, channels] with square windows.
+func AvgPool2D(s *Scope, x tf.Output, size, stride int, padding string) tf.Output {
+	sz, st := int64(size), int64(stride)
+	return AvgPool(s, x, []int64{1, sz, sz, 1}, []int64{1, st, st, 1}, padding)
+}
+
+// GlobalAvgPool averages channels-last inputs over all their spatial
+// dimensions, e.g. from [batch, height, width, channels] to
+// [batch, channels]. If the rank of the input is unknown, the spatial
+// dimensions are determined when the graph is run.
+func GlobalAvgPool(s *Scope, x tf.Output) tf.Output {
+	rank := x.Shape().NumDimensions()
+	if rank < 0 {
+		one := Const(s, int32(1))
+		return Mean(s, x, Range(s, one, Sub(s, Rank(s, x), one), one))
+	}
+	if rank < 3 {
+		s.UpdateErr("GlobalAvgPool", fmt.Errorf("inputs need spatial dimensions, got %v", x.Shape()))
+		return tf.Output{}
+	}
+	axes := make([]int32, 0, rank-2)
+	for i := 1; i < rank-1; i++ {
+		axes = append(axes, int32(i))
+	}
+	return Mean(s, x, Const(s, axes))
+}
+
+// kernelVariable creates and tags the kernel variable of a layer.
+func (s *Scope) kernelVariable(shape tf.Shape, dtype tf.DataType, tags []VarTag) tf.Output {
+	kernel := VariableV2(s, shape, dtype)
+	if len(tags) == 0 {
+		tags = []VarTag{TagInitXavierNormal, TagTrainable, TagDecayL2}
+	}
+	s.tagVariable(kernel, tags...)
+	return CheckNumerics(s, kernel, kernel.Op.Name())
+}
+
+// biasVariable creates and tags the bias variable of a layer.
+func (s *Scope) biasVariable(channels int64, dtype tf.DataType, tags []VarTag) tf.Output {
+	bias := VariableV2(s, tf.MakeShape(channels), dtype)
+	if len(tags) == 0 {
+		tags = []VarTag{TagInitEpsUniform, TagTrainable, TagDecayL1}
+	}
+	s.tagVariable(bias, tags...)
+	return CheckNumerics(s, bias, bias.Op.Name())
+}
+
+// channelBias adds a bias per channel to the channels-last input.
+func (s *Scope) channelBias(x tf.Output, channels int64, tags []VarTag) tf.Output {
+	return BiasAdd(s, x, s.biasVariable(channels, x.DataType(), tags))
+}
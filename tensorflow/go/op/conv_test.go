@@ -0,0 +1,73 @@
+package op
+
+import (
+	"reflect"
+	"testing"
+
+	tf "github.com/hdu-hh/tensorflow/tensorflow/go"
+)
+
+func TestConvLayers(t *testing.T) {
+	s := NewScope()
+	x1 := Placeholder(s, tf.Float, PlaceholderShape(tf.MakeShape(2, 10, 3)))
+	x2 := Placeholder(s, tf.Float, PlaceholderShape(tf.MakeShape(2, 8, 8, 3)))
+	x3 := Placeholder(s, tf.Float, PlaceholderShape(tf.MakeShape(2, 4, 4, 4, 3)))
+	x4 := Placeholder(s, tf.Float) // of unknown rank
+	tests := []struct {
+		name string
+		y    tf.Output
+		want []int64
+	}{
+		{"Conv1DLayer", Conv1DLayer(s, x1, 4, 3, 1, "SAME"), []int64{2, 10, 4}},
+		{"Conv1DLayer/VALID", Conv1DLayer(s, x1, 4, 3, 2, "VALID"), []int64{2, 4, 4}},
+		{"Conv2DLayer", Conv2DLayer(s, x2, 5, 3, 2, "SAME"), []int64{2, 4, 4, 5}},
+		{"Conv3DLayer", Conv3DLayer(s, x3, 2, 3, 1, "VALID"), []int64{2, 2, 2, 2, 2}},
+		{"DepthwiseConv2DLayer", DepthwiseConv2DLayer(s, x2, 2, 3, 1, "SAME"), []int64{2, 8, 8, 6}},
+		{"Conv2DTransposeLayer", Conv2DTransposeLayer(s, x2, 4, 3, 2, "SAME"), []int64{2, 16, 16, 4}},
+		{"Conv2DTransposeLayer/VALID", Conv2DTransposeLayer(s, x2, 4, 3, 2, "VALID"), []int64{2, 17, 17, 4}},
+		{"MaxPool2D", MaxPool2D(s, x2, 2, 2, "VALID"), []int64{2, 4, 4, 3}},
+		{"AvgPool2D", AvgPool2D(s, x2, 3, 1, "SAME"), []int64{2, 8, 8, 3}},
+		{"GlobalAvgPool", GlobalAvgPool(s, x3), []int64{2, 3}},
+		{"GlobalAvgPool/unknown rank", GlobalAvgPool(s, x4), []int64{2, 3}},
+	}
+	// a kernel and a bias per convolution
+	if got := len(s.GetParams()); got != 14 {
+		t.Errorf("Got %d trainable parameters, want 14", got)
+	}
+	sess := newTestSession(t, s)
+	feeds := map[tf.Output]*tf.Tensor{
+		x1: zeros(t, 2, 10, 3),
+		x2: zeros(t, 2, 8, 8, 3),
+		x3: zeros(t, 2, 4, 4, 4, 3),
+		x4: zeros(t, 2, 8, 8, 3),
+	}
+	for _, test := range tests {
+		fetched, err := sess.Run(feeds, []tf.Output{test.y}, nil)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if got := fetched[0].Shape(); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got shape %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestFans(t *testing.T) {
+	tests := []struct {
+		shape         tf.Shape
+		fanIn, fanOut float64
+	}{
+		{tf.ScalarShape(), 1, 1},
+		{tf.MakeShape(7), 7, 7},
+		{tf.MakeShape(3, 5), 3, 5},
+		{tf.MakeShape(3, 3, 4, 8), 36, 72},
+		{tf.MakeShape(2, 2, 2, 3, 5), 24, 40},
+	}
+	for _, test := range tests {
+		fanIn, fanOut := fans(test.shape)
+		if fanIn != test.fanIn || fanOut != test.fanOut {
+			t.Errorf("fans(%v) = %v, %v, want %v, %v", test.shape, fanIn, fanOut, test.fanIn, test.fanOut)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package op
+
+import (
+	"fmt"
+
+	tf "github.com/hdu-hh/tensorflow/tensorflow/go"
+)
+
+// eagerState is shared between all derivatives of an eager root scope.
+type eagerState struct {
+	ctx     *tf.Context
+	handles map[tf.Output]*tf.TensorHandle
+}
+
+// NewEagerScope creates a Scope which executes every operation immediately
+// in the context ctx when it is added, e.g. when calling the operation
+// wrappers. The results can be inspected with [Scope.Value] and
+// [Scope.Handle] right away, which is handy for debugging and scripting.
+//
+// The operations are also added to the graph of the scope to track their
+// shapes and types. The eager results are kept as long as the scope is used.
+func NewEagerScope(ctx *tf.Context) *Scope {
+	s := NewScope()
+	s.eager = &eagerState{ctx: ctx, handles: make(map[tf.Output]*tf.TensorHandle)}
+	return s
+}
+
+// IsEager reports whether the scope executes its operations eagerly.
+func (s *Scope) IsEager() bool {
+	return s.eager != nil
+}
+
+// Handle returns the handle to the eagerly computed output of an eager scope.
+func (s *Scope) Handle(output tf.Output) (*tf.TensorHandle, error) {
+	if s.eager == nil {
+		return nil, fmt.Errorf("scope does not execute eagerly")
+	}
+	th, ok := s.eager.handles[output]
+	if !ok {
+		return nil, fmt.Errorf("output %d of operation %q was not computed eagerly", output.Index, output.Op.Name())
+	}
+	return th, nil
+}
+
+// Value returns the eagerly computed output of an eager scope as a tensor.
+func (s *Scope) Value(output tf.Output) (*tf.Tensor, error) {
+	th, err := s.Handle(output)
+	if err != nil {
+		return nil, err
+	}
+	return th.ToTensor()
+}
+
+// execute executes the operation op which was added to the graph
+// according to args.
+func (e *eagerState) execute(op *tf.Operation, args tf.OpSpec) error {
+	inputs := make([]tf.EagerInput, len(args.Input))
+	for i, in := range args.Input {
+		switch in := in.(type) {
+		case tf.Output:
+			th, err := e.handle(in)
+			if err != nil {
+				return err
+			}
+			inputs[i] = th
+		case tf.OutputList:
+			list := make(tf.TensorHandleList, len(in))
+			for j, out := range in {
+				th, err := e.handle(out)
+				if err != nil {
+					return err
+				}
+				list[j] = th
+			}
+			inputs[i] = list
+		}
+	}
+	outputs, err := e.ctx.Execute(tf.EagerOpSpec{
+		Type:       args.Type,
+		Attrs:      args.Attrs,
+		Device:     args.Device,
+		NumOutputs: op.NumOutputs(),
+	}, inputs...)
+	if err != nil {
+		return err
+	}
+	for i, th := range outputs {
+		e.handles[op.Output(i)] = th
+	}
+	return nil
+}
+
+func (e *eagerState) handle(output tf.Output) (*tf.TensorHandle, error) {
+	th, ok := e.handles[output]
+	if !ok {
+		return nil, fmt.Errorf("input %q:%d was not computed eagerly", output.Op.Name(), output.Index)
+	}
+	return th, nil
+}
@@ -0,0 +1,105 @@
+package op
+
+import (
+	"fmt"
+	"testing"
+
+	tf "github.com/hdu-hh/tensorflow/tensorflow/go"
+)
+
+func ExampleNewEagerScope() {
+	ctx, err := tf.NewContext(nil)
+	if err != nil {
+		panic(err)
+	}
+	s := NewEagerScope(ctx)
+	x := Const(s, [][]float32{{1, 2}, {3, 4}})
+	y := MatMul(s, x, x)
+	value, err := s.Value(y)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(value.Value())
+	// Output: [[7 10] [15 22]]
+}
+
+func ExampleNewEagerScope_gradientTape() {
+	ctx, err := tf.NewContext(nil)
+	if err != nil {
+		panic(err)
+	}
+	s := NewEagerScope(ctx)
+	x := Const(s, []float32{1, 2, 3})
+	xh, err := s.Handle(x)
+	if err != nil {
+		panic(err)
+	}
+	tape := ctx.NewGradientTape()
+	tape.Watch(xh)
+	loss := Sum(s, Square(s, x), Const(s, int32(0)))
+	lossh, err := s.Handle(loss)
+	if err != nil {
+		panic(err)
+	}
+	grads, err := tape.Gradient(lossh, xh)
+	if err != nil {
+		panic(err)
+	}
+	value, err := grads[0].ToTensor()
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(value.Value())
+	// Output: [2 4 6]
+}
+
+func TestEagerScope(t *testing.T) {
+	ctx, err := tf.NewContext(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewEagerScope(ctx)
+	if !s.IsEager() || NewScope().IsEager() {
+		t.Fatalf("IsEager does not tell eager scopes")
+	}
+	sub := s.SubScope("sub")
+	parts := Split(sub, Const(sub, int32(0)), Const(sub, []int64{1, 2, 3, 4}), 2)
+	sum := AddN(s.WithDevice(""), parts)
+	value, err := s.Value(sum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := value.Value(), []int64{4, 6}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Got %v, want %v", got, want)
+	}
+	if _, err := s.Value(tf.Output{Op: sum.Op, Index: 1}); err == nil {
+		t.Errorf("Value of a missing output did not fail")
+	}
+
+	// operations which cannot run eagerly report their errors like
+	// operations which cannot be added to the graph
+	defer func() {
+		if recover() == nil || s.Err() == nil {
+			t.Errorf("Executing a placeholder did not fail")
+		}
+	}()
+	Placeholder(s, tf.Float)
+}
+
+func TestEagerScopeInvalidOperation(t *testing.T) {
+	ctx, err := tf.NewContext(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewEagerScope(ctx)
+	x := Const(s, []float32{1, 2, 3})
+	y := Const(s, [][]float32{{1, 2}})
+	// the failure to add the operation to the graph is reported as the
+	// scope error instead of executing a missing operation
+	defer func() {
+		if r := recover(); r != s.Err() || s.Err() == nil {
+			t.Errorf("Got panic %v with scope error %v", r, s.Err())
+		}
+	}()
+	MatMul(s, x, y)
+}
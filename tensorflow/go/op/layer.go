@@ -1,6 +1,8 @@
 package op
 
 import (
+	"fmt"
+
 	tf "github.com/hdu-hh/tensorflow/tensorflow/go"
 )
 
@@ -44,3 +46,63 @@ func MLP(s *Scope, x tf.Output, outX int, actFunc ActFunc, tags ...VarTag) tf.Ou
 	}
 	return y
 }
+
+// Dropout randomly sets elements of x to zero with the probability rate and
+// scales the kept elements by 1/(1-rate) to preserve the expected sum.
+// It only drops elements in the training phase (see [Scope.Training]).
+func Dropout(s *Scope, x tf.Output, rate float32) tf.Output {
+	if rate <= 0 {
+		return x
+	}
+	return s.dropout(x, Shape(s, StopGradient(s, x)), rate)
+}
+
+// SpatialDropout randomly sets whole channels of x of the shape
+// [batch, spatial..., channels] to zero with the probability rate and scales
+// the kept channels by 1/(1-rate), which regularizes convolutions better than
+// [Dropout] since neighboring elements are strongly correlated.
+// It only drops channels in the training phase (see [Scope.Training]).
+func SpatialDropout(s *Scope, x tf.Output, rate float32) tf.Output {
+	rank := x.Shape().NumDimensions()
+	if rank < 3 {
+		s.UpdateErr("SpatialDropout", fmt.Errorf("input of shape %v has no spatial axes", x.Shape()))
+		return tf.Output{}
+	}
+	if rate <= 0 {
+		return x
+	}
+	ones := make([]int32, rank-2)
+	for i := range ones {
+		ones[i] = 1
+	}
+	r := int32(rank)
+	noiseShape := ConcatV2(s, []tf.Output{s.dynamicDims(x, 0, 1), Const(s, ones), s.dynamicDims(x, r-1, r)}, Const(s, int32(0)))
+	return s.dropout(x, noiseShape, rate)
+}
+
+// dropout drops the elements of x with the probability rate in the training
+// phase, where the mask has the noise shape broadcastable to x.
+func (s *Scope) dropout(x, noiseShape tf.Output, rate float32) tf.Output {
+	// floor(keep + uniform) is 1 with the probability keep and 0 otherwise
+	keep := Cast(s, Const(s, 1-rate), x.DataType())
+	mask := Div(s, Floor(s, Add(s, keep, s.randomUniform(noiseShape, x.DataType()))), keep)
+	// the mask is 1 outside of the training phase
+	training := Cast(s, s.Training(), x.DataType())
+	one := OnesLike(s, training)
+	mask = StopGradient(s, Add(s, one, Mul(s, training, Sub(s, mask, one))))
+	return Mul(s, x, mask)
+}
+
+// GaussianNoise adds normally distributed noise with a mean of zero and the
+// standard deviation stddev to x, which regularizes like data augmentation.
+// It only adds noise in the training phase (see [Scope.Training]).
+func GaussianNoise(s *Scope, x tf.Output, stddev float32) tf.Output {
+	if stddev <= 0 {
+		return x
+	}
+	seed, seed2 := s.randomSeeds()
+	noise := RandomStandardNormal(s, Shape(s, StopGradient(s, x)), x.DataType(),
+		RandomStandardNormalSeed(seed), RandomStandardNormalSeed2(seed2))
+	scale := Mul(s, Cast(s, s.Training(), x.DataType()), Cast(s, Const(s, stddev), x.DataType()))
+	return Add(s, x, StopGradient(s, Mul(s, noise, scale)))
+}
@@ -1,6 +1,10 @@
 package op
 
-import tf "github.com/hdu-hh/tensorflow/tensorflow/go"
+import (
+	"fmt"
+
+	tf "github.com/hdu-hh/tensorflow/tensorflow/go"
+)
 
 type NormFunc func(*Scope, tf.Output) tf.Output
 
@@ -24,18 +28,19 @@ func NormL2(s *Scope, x tf.Output) tf.Output {
 
 // LayerNorm normalizes each batch element
 func LayerNorm(s *Scope, x tf.Output) tf.Output {
-	batches := x.Shape().Size(0)
-	l := Reshape(s, x, Const(s, []int64{batches, -1}))
+	shape := ConcatV2(s, []tf.Output{s.dynamicDims(x, 0, 1), Const(s, []int32{-1})}, Const(s, int32(0)))
+	l := Reshape(s, x, shape)
 	axis1 := Const(s, int32(1))
 	mean1 := Mean(s, l, axis1, MeanKeepDims(true))
 	mean2 := Mean(s, Square(s, l), axis1, MeanKeepDims(true))
 	eps := Const(s, float32(1e-6))
 	rvari := Rsqrt(s, Add(s, eps, Sub(s, mean2, Square(s, mean1))))
-	y := Mul(s, rvari, Sub(s, x, mean1))
-	return y
+	y := Mul(s, rvari, Sub(s, l, mean1))
+	return Reshape(s, y, Shape(s, x))
 }
 
-// BatchNorm normalizes along the batch axis
+// BatchNorm normalizes along the batch axis using the statistics of the batch.
+// Use [BatchNormalization] for a layer which also works for inference.
 func BatchNorm(s *Scope, x tf.Output) tf.Output {
 	axis0 := Const(s, int32(0))
 	mean1 := Mean(s, x, axis0)
@@ -45,3 +50,223 @@ func BatchNorm(s *Scope, x tf.Output) tf.Output {
 	y := Mul(s, rvari, Sub(s, x, mean1))
 	return y
 }
+
+// NormOpts are the options of the normalization layers [LayerNormalization],
+// [BatchNormalization], [GroupNorm] and [RMSNorm].
+type NormOpts struct {
+	// Axes to normalize over, which may be negative to count from the last
+	// axis. The layers have their own defaults if it is empty.
+	Axes []int
+
+	// Epsilon is added to the variances to avoid divisions by zero.
+	// Defaults to 1e-6.
+	Epsilon float32
+
+	// Momentum of the moving statistics of [BatchNormalization], which are
+	// updated to momentum*moving + (1-momentum)*batch in training.
+	// Defaults to 0.99.
+	Momentum float32
+
+	// Training is a boolean scalar which selects the training mode, e.g. a
+	// placeholder. In training, [BatchNormalization] uses the statistics of
+	// the batch and updates its moving statistics. Otherwise it uses the
+	// moving statistics. If Training.Op is nil, the training phase of the
+	// scope is used (see [Scope.Training]).
+	Training tf.Output
+
+	// Tags of the trainable scales and offsets besides their initializers,
+	// which initialize them to ones and zeros. Defaults to TagTrainable.
+	Tags []VarTag
+}
+
+func (opts NormOpts) epsilon() float32 {
+	if opts.Epsilon <= 0 {
+		return 1e-6
+	}
+	return opts.Epsilon
+}
+
+// LayerNormalization normalizes each batch element over the axes, by default
+// the last axis, and applies a trainable scale and offset per element of the
+// normalized axes (see https://arxiv.org/abs/1607.06450).
+func LayerNormalization(s *Scope, x tf.Output, opts NormOpts) tf.Output {
+	axes, ok := s.normAxes(x, opts.Axes, -1)
+	if !ok {
+		return tf.Output{}
+	}
+	mean, variance := moments(s, x, axes)
+	y := normalize(s, x, mean, variance, opts.epsilon())
+	return s.scaleOffset(y, paramShape(x, axes, true), opts.Tags, true)
+}
+
+// BatchNormalization normalizes x over the axes, by default all but the last
+// axis, and applies a trainable scale and offset per channel
+// (see https://arxiv.org/abs/1502.03167).
+//
+// In training, it normalizes with the statistics of the batch and updates
+// the moving statistics whenever its output is computed. Otherwise it
+// normalizes with the moving statistics (see [NormOpts]).
+// The moving statistics are variables initialized with TagInitZeros and
+// TagInitOnes, so that they are initialized and saved, but not trained.
+func BatchNormalization(s *Scope, x tf.Output, opts NormOpts) tf.Output {
+	rank := x.Shape().NumDimensions()
+	defaults := make([]int, 0, rank)
+	for i := 0; i < rank-1; i++ {
+		defaults = append(defaults, i)
+	}
+	axes, ok := s.normAxes(x, opts.Axes, defaults...)
+	if !ok {
+		return tf.Output{}
+	}
+	shape := paramShape(x, axes, false)
+	movingMean := VariableV2(s, shape, x.DataType())
+	movingVariance := VariableV2(s, shape, x.DataType())
+	s.tagVariable(movingMean, TagInitZeros)
+	s.tagVariable(movingVariance, TagInitOnes)
+
+	mean, variance := moments(s, x, axes)
+	momentum := opts.Momentum
+	if momentum <= 0 {
+		momentum = 0.99
+	}
+	// the moving statistics are only updated in training, where training is 1
+	phase := opts.Training
+	if phase.Op == nil {
+		phase = s.Training()
+	}
+	training := Cast(s, phase, x.DataType())
+	rate := Mul(s, training, Cast(s, Const(s, 1-momentum), x.DataType()))
+	updates := []*tf.Operation{
+		AssignSub(s, movingMean, Mul(s, rate, Sub(s, movingMean, StopGradient(s, mean)))).Op,
+		AssignSub(s, movingVariance, Mul(s, rate, Sub(s, movingVariance, StopGradient(s, variance)))).Op,
+	}
+	inference := Sub(s, OnesLike(s, training), training)
+	mean = Add(s, Mul(s, training, mean), Mul(s, inference, movingMean))
+	variance = Add(s, Mul(s, training, variance), Mul(s, inference, movingVariance))
+
+	y := normalize(s, x, mean, variance, opts.epsilon())
+	y = Identity(s.WithControlDependencies(updates...), y)
+	return s.scaleOffset(y, shape, opts.Tags, true)
+}
+
+// GroupNorm normalizes each batch element over groups of channels of x of
+// the shape [batch, ..., channels] and applies a trainable scale and offset
+// per channel (see https://arxiv.org/abs/1803.08494). The channels must be
+// divisible by the number of groups. The Axes of the options are ignored.
+func GroupNorm(s *Scope, x tf.Output, groups int, opts NormOpts) tf.Output {
+	rank, channels := x.Shape().NumDimensions(), x.Shape().Size(-1)
+	if rank < 2 || channels <= 0 || groups <= 0 || channels%int64(groups) != 0 {
+		s.UpdateErr("GroupNorm", fmt.Errorf("cannot split the channels of shape %v into %d groups", x.Shape(), groups))
+		return tf.Output{}
+	}
+	g := int32(groups)
+	shape := ConcatV2(s, []tf.Output{s.dynamicDims(x, 0, int32(rank-1)), Const(s, []int32{g, int32(channels) / g})}, Const(s, int32(0)))
+	grouped := Reshape(s, x, shape)
+	// normalize over all axes but the batch and group axes
+	axes := make([]int, 0, rank-1)
+	for i := 1; i < rank-1; i++ {
+		axes = append(axes, i)
+	}
+	axes = append(axes, rank)
+	mean, variance := moments(s, grouped, axes)
+	y := Reshape(s, normalize(s, grouped, mean, variance, opts.epsilon()), Shape(s, x))
+	return s.scaleOffset(y, paramShape(x, []int{rank - 1}, false), opts.Tags, true)
+}
+
+// RMSNorm normalizes each batch element by the root mean square over the
+// axes, by default the last axis, and applies a trainable scale per element
+// of the normalized axes (see https://arxiv.org/abs/1910.07467).
+func RMSNorm(s *Scope, x tf.Output, opts NormOpts) tf.Output {
+	axes, ok := s.normAxes(x, opts.Axes, -1)
+	if !ok {
+		return tf.Output{}
+	}
+	meanSquare := Mean(s, Square(s, x), Const(s, int32Slice(axes)), MeanKeepDims(true))
+	eps := Cast(s, Const(s, opts.epsilon()), x.DataType())
+	y := Mul(s, x, Rsqrt(s, Add(s, meanSquare, eps)))
+	return s.scaleOffset(y, paramShape(x, axes, true), opts.Tags, false)
+}
+
+// normAxes returns the non-negative axes, or the defaults if axes is empty.
+func (s *Scope) normAxes(x tf.Output, axes []int, defaults ...int) ([]int, bool) {
+	rank := x.Shape().NumDimensions()
+	if len(axes) == 0 {
+		axes = defaults
+	}
+	result := make([]int, len(axes))
+	for i, axis := range axes {
+		if axis < 0 {
+			axis += rank
+		}
+		if rank < 0 || axis < 0 || axis >= rank {
+			s.UpdateErr("normalization", fmt.Errorf("invalid axis %d for shape %v", axes[i], x.Shape()))
+			return nil, false
+		}
+		result[i] = axis
+	}
+	return result, true
+}
+
+// paramShape returns the shape of the parameters of a normalization, which
+// has the sizes of x along the axes (or the other axes if along is false)
+// and 1 for the other axes to be broadcastable to x.
+func paramShape(x tf.Output, axes []int, along bool) tf.Shape {
+	dims := make([]int64, x.Shape().NumDimensions())
+	for i := range dims {
+		dims[i] = 1
+		if !along {
+			dims[i] = x.Shape().Size(i)
+		}
+	}
+	for _, axis := range axes {
+		dims[axis] = 1
+		if along {
+			dims[axis] = x.Shape().Size(axis)
+		}
+	}
+	return tf.MakeShape(dims...)
+}
+
+// moments returns the mean and the variance of x over the axes, keeping the
+// reduced dimensions.
+func moments(s *Scope, x tf.Output, axes []int) (mean, variance tf.Output) {
+	axesConst := Const(s, int32Slice(axes))
+	mean = Mean(s, x, axesConst, MeanKeepDims(true))
+	variance = Mean(s, Square(s, Sub(s, x, mean)), axesConst, MeanKeepDims(true))
+	return mean, variance
+}
+
+// normalize returns (x - mean) / sqrt(variance + eps).
+func normalize(s *Scope, x, mean, variance tf.Output, eps float32) tf.Output {
+	epsConst := Cast(s, Const(s, eps), x.DataType())
+	return Mul(s, Sub(s, x, mean), Rsqrt(s, Add(s, variance, epsConst)))
+}
+
+// scaleOffset multiplies x by a trainable scale and adds a trainable
+// offset if requested.
+func (s *Scope) scaleOffset(x tf.Output, shape tf.Shape, tags []VarTag, offset bool) tf.Output {
+	if len(tags) == 0 {
+		tags = []VarTag{TagTrainable}
+	}
+	if !shape.IsFullySpecified() {
+		s.UpdateErr("normalization", fmt.Errorf("the sizes of the parameters %v must be known", shape))
+		return tf.Output{}
+	}
+	scale := VariableV2(s, shape, x.DataType())
+	s.tagVariable(scale, append([]VarTag{TagInitOnes}, tags...)...)
+	y := Mul(s, x, scale)
+	if offset {
+		bias := VariableV2(s, shape, x.DataType())
+		s.tagVariable(bias, append([]VarTag{TagInitZeros}, tags...)...)
+		y = Add(s, y, bias)
+	}
+	return y
+}
+
+func int32Slice(values []int) []int32 {
+	result := make([]int32, len(values))
+	for i, v := range values {
+		result[i] = int32(v)
+	}
+	return result
+}
@@ -2,6 +2,8 @@ package op
 
 import (
 	"fmt"
+	"math"
+	"testing"
 
 	tf "github.com/hdu-hh/tensorflow/tensorflow/go"
 )
@@ -79,3 +81,81 @@ func ExampleBatchNorm() {
 	// +1.41414,+1.39971,+1.33631,
 	// -0.71933,-0.87482,-1.06904,
 }
+
+func TestNormalizationLayers(t *testing.T) {
+	s := NewScope()
+	x := Placeholder(s, tf.Float, PlaceholderShape(tf.MakeShape(-1, 2, 4)))
+	tests := []struct {
+		name string
+		y    tf.Output
+		want [][]float32
+	}{
+		{"LayerNorm", LayerNorm(s, x), [][]float32{
+			{-1.5275, -1.0911, -0.6547, -0.2182}, {0.2182, 0.6547, 1.0911, 1.5275},
+		}},
+		{"LayerNormalization", LayerNormalization(s, x, NormOpts{}), [][]float32{
+			{-1.3416, -0.4472, 0.4472, 1.3416}, {-1.3416, -0.4472, 0.4472, 1.3416},
+		}},
+		{"GroupNorm", GroupNorm(s, x, 2, NormOpts{}), [][]float32{
+			{-1.2127, -0.7276, -1.2127, -0.7276}, {0.7276, 1.2127, 0.7276, 1.2127},
+		}},
+		{"RMSNorm", RMSNorm(s, x, NormOpts{}), [][]float32{
+			{0.3651, 0.7303, 1.0954, 1.4606}, {0.7581, 0.9097, 1.0613, 1.2130},
+		}},
+	}
+	if got := len(s.taggedVariables(TagTrainable)); got != 5 {
+		t.Errorf("Got %d trainable variables, want 5", got)
+	}
+	sess := newTestSession(t, s)
+	feeds := map[tf.Output]*tf.Tensor{x: mustTensor(t, [][][]float32{{{1, 2, 3, 4}, {5, 6, 7, 8}}})}
+	for _, test := range tests {
+		if test.y.Shape().String() != x.Shape().String() {
+			t.Errorf("%s: got static shape %v, want %v", test.name, test.y.Shape(), x.Shape())
+		}
+		fetched, err := sess.Run(feeds, []tf.Output{test.y}, nil)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		for i, row := range fetched[0].Value().([][][]float32)[0] {
+			for j, v := range row {
+				if math.Abs(float64(v-test.want[i][j])) > 1e-3 {
+					t.Errorf("%s: got %v, want %v", test.name, row, test.want[i])
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestBatchNormalization(t *testing.T) {
+	s := NewScope()
+	x := Placeholder(s, tf.Float, PlaceholderShape(tf.MakeShape(-1, 2)))
+	training := Placeholder(s, tf.Bool, PlaceholderShape(tf.ScalarShape()))
+	y := BatchNormalization(s, x, NormOpts{Momentum: 0.5, Training: training})
+	sess := newTestSession(t, s)
+	run := func(value [][]float32, train bool) [][]float32 {
+		t.Helper()
+		feeds := map[tf.Output]*tf.Tensor{x: mustTensor(t, value), training: mustTensor(t, train)}
+		fetched, err := sess.Run(feeds, []tf.Output{y}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fetched[0].Value().([][]float32)
+	}
+
+	// training normalizes with the batch statistics mean=[2, 20], var=[1, 100]
+	// and moves the moving statistics to mean=[1, 10], var=[1, 50.5]
+	got := run([][]float32{{1, 10}, {3, 30}}, true)
+	if want := []float32{-1, -1, 1, 1}; !approxEqual(append(got[0], got[1]...), want) {
+		t.Errorf("Got %v in training, want %v", got, want)
+	}
+	// inference normalizes with the moving statistics without updating them
+	want := [][]float32{{1, float32(10 / math.Sqrt(50.5))}}
+	for i := 0; i < 2; i++ {
+		if got := run([][]float32{{2, 20}}, false); math.Abs(float64(got[0][0]-want[0][0])) > 1e-3 ||
+			math.Abs(float64(got[0][1]-want[0][1])) > 1e-3 {
+			t.Errorf("Got %v in inference %d, want %v", got, i, want)
+		}
+	}
+}
@@ -17,6 +17,7 @@ type optimizerBase struct {
 	losses    []tf.Output
 	learnRate tf.Output
 	stepOp    *tf.Operation // operation performed in a step: usually a NoOp with control dependencies
+	phase     *phaseState   // the training phase fed in a step
 }
 
 func (base optimizerBase) getName() string         { return base.name }
@@ -25,7 +26,7 @@ func (base optimizerBase) getLearnRate() tf.Output { return base.learnRate }
 
 func (base *optimizerBase) Step(sess *tf.Session, feeds tf.FeedMap, fetches []tf.Output, targets []*tf.Operation) []*tf.Tensor {
 	targets = append(targets, base.stepOp)
-	fetched, err := sess.Run(feeds, fetches, targets)
+	fetched, err := sess.Run(base.phase.trainingFeeds(feeds), fetches, targets)
 	if err != nil {
 		panic(err)
 	}
@@ -47,7 +48,7 @@ func OptimizerSGD(s *Scope, losses []tf.Output, learnRate float32, tags ...VarTa
 		updateOps[i] = AssignSub(s, params[i], newGrad).Op
 	}
 	stepOp := NoOp(s.WithControlDependencies(updateOps...))
-	return &optimizerBase{"SGD", params, losses, lrConst, stepOp}
+	return &optimizerBase{"SGD", params, losses, lrConst, stepOp, s.phase}
 }
 
 // OptimizerAdam is an optimizer with adaptive momentum
@@ -94,7 +95,7 @@ func OptimizerAdam(s *Scope, losses []tf.Output, learnRate, alpha, beta1, beta2
 		updateOps = append(updateOps, a1.Op, a2.Op, a3.Op)
 	}
 	stepOp := NoOp(s.WithControlDependencies(updateOps...))
-	return &optimizerBase{"Adam", params, losses, lrConst, stepOp}
+	return &optimizerBase{"Adam", params, losses, lrConst, stepOp, s.phase}
 }
 
 // OptimizerLayla is an optimizer with layer adaptive exponential learning rate adaption
@@ -148,7 +149,7 @@ func OptimizerLayla(s *Scope, losses []tf.Output, tags ...VarTag) Optimizer {
 		lrAssign = Assign(scd, learnRates, splitLRs[0])
 	}
 	stepOp := NoOp(scd.WithControlDependencies(lrAssign.Op))
-	return &optimizerBase{"Layla", params, losses, learnRates, stepOp}
+	return &optimizerBase{"Layla", params, losses, learnRates, stepOp, s.phase}
 }
 
 // weightDecay is an optimizer which help generalization by reducing weights
@@ -205,7 +206,7 @@ func WeightDecay(s *Scope, refOpt Optimizer, decayRate float32) Optimizer {
 	stepOp := NoOp(s.WithControlDependencies(updateOps...))
 	return &weightDecay{
 		lossDescend: refOpt,
-		weightDecay: optimizerBase{"wdecay", params, losses, decayConst, stepOp},
+		weightDecay: optimizerBase{"wdecay", params, losses, decayConst, stepOp, s.phase},
 	}
 }
 
@@ -0,0 +1,75 @@
+package op
+
+import (
+	tf "github.com/hdu-hh/tensorflow/tensorflow/go"
+)
+
+// phaseState is shared between all derivatives of a root scope.
+type phaseState struct {
+	root     *Scope
+	training tf.Output // created on the first use
+	seeded   bool
+	seed     int64
+	opSeeds  int64 // number of random operations with derived seeds
+}
+
+// Training returns a boolean scalar which is true in the training phase,
+// which enables e.g. [Dropout] and the batch statistics of
+// [BatchNormalization]. It is a placeholder defaulting to false, which is
+// created once per root scope. [Optimizer.Step] feeds it with true unless
+// the feeds contain it already.
+func (s *Scope) Training() tf.Output {
+	p := s.phase
+	if p.training.Op == nil {
+		rs := p.root.SubScope("training")
+		p.training = PlaceholderWithDefault(rs, Const(rs, false), tf.ScalarShape())
+	}
+	return p.training
+}
+
+// trainingFeeds returns the feeds with the training placeholder set to true
+// if it has been created and is not fed yet.
+func (p *phaseState) trainingFeeds(feeds tf.FeedMap) tf.FeedMap {
+	if p == nil || p.training.Op == nil {
+		return feeds
+	}
+	if _, ok := feeds[p.training]; ok {
+		return feeds
+	}
+	training, err := tf.NewTensor(true)
+	if err != nil {
+		panic(err)
+	}
+	result := make(tf.FeedMap, len(feeds)+1)
+	for k, v := range feeds {
+		result[k] = v
+	}
+	result[p.training] = training
+	return result
+}
+
+// SetSeed sets the seed of the random operations created afterwards in all
+// derivatives of the root scope, like the variable initializers and
+// [Dropout], so that graphs built by the same code compute the same random
+// values. Each of the random operations gets its own seed derived from the
+// order of their creation.
+func (s *Scope) SetSeed(seed int64) {
+	s.phase.seeded, s.phase.seed, s.phase.opSeeds = true, seed, 0
+}
+
+// randomSeeds returns the seeds of the next random operation,
+// which are zero for nondeterministic random values if no seed was set.
+func (s *Scope) randomSeeds() (seed, seed2 int64) {
+	p := s.phase
+	if !p.seeded {
+		return 0, 0
+	}
+	p.opSeeds++
+	return p.seed, p.opSeeds
+}
+
+// randomUniform returns uniform random values in [0, 1) of the shape.
+func (s *Scope) randomUniform(shape tf.Output, dtype tf.DataType) tf.Output {
+	seed, seed2 := s.randomSeeds()
+	return RandomUniform(s, shape, dtype, RandomUniformSeed(seed), RandomUniformSeed2(seed2))
+}
@@ -0,0 +1,79 @@
+package op
+
+import (
+	"reflect"
+	"testing"
+
+	tf "github.com/hdu-hh/tensorflow/tensorflow/go"
+)
+
+func TestTrainingPhase(t *testing.T) {
+	s := NewScope()
+	ones := make([][][]float32, 2)
+	for b := range ones {
+		ones[b] = [][]float32{{1, 1, 1, 1}, {1, 1, 1, 1}, {1, 1, 1, 1}}
+	}
+	x := Const(s, ones)
+	spatial := SpatialDropout(s, x, 0.5)
+	noisy := GaussianNoise(s, x, 1)
+	y := Linear(s, Flatten(s, noisy), 1)
+	loss := Mean(s, Flatten(s, Square(s, y)), Const(s, int32(0)))
+	opti := OptimizerSGD(s, []tf.Output{loss}, 1e-3)
+	training := s.Training()
+	sess := newTestSession(t, s)
+
+	// the layers are the identity outside of the training phase
+	fetched, err := sess.Run(nil, []tf.Output{training, spatial, noisy}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fetched[0].Value().(bool) {
+		t.Errorf("Training phase is active by default")
+	}
+	for _, f := range fetched[1:] {
+		if got := f.Value(); !reflect.DeepEqual(got, ones) {
+			t.Errorf("Got %v outside of the training phase, want %v", got, ones)
+		}
+	}
+
+	// the optimizer steps are in the training phase
+	fetched = opti.Step(sess, nil, []tf.Output{training, spatial, noisy}, nil)
+	if !fetched[0].Value().(bool) {
+		t.Errorf("Training phase is not active in an optimizer step")
+	}
+	for b, channels := range fetched[1].Value().([][][]float32) {
+		for i, v := range channels[0] {
+			if v != 0 && v != 2 {
+				t.Errorf("Got %v in channel %d of batch %d, want 0 or 2", v, i, b)
+			}
+			for _, values := range channels[1:] {
+				if values[i] != v {
+					t.Errorf("Channel %d of batch %d was not dropped as a whole: %v", i, b, channels)
+				}
+			}
+		}
+	}
+	if got := fetched[2].Value(); reflect.DeepEqual(got, ones) {
+		t.Errorf("GaussianNoise added no noise in the training phase")
+	}
+}
+
+func TestSetSeed(t *testing.T) {
+	run := func() []interface{} {
+		s := NewScope()
+		s.SetSeed(42)
+		x := Const(s, [][]float32{{1, 2, 3, 4}, {5, 6, 7, 8}})
+		y := Linear(s, Dropout(s, x, 0.5), 3)
+		noisy := GaussianNoise(s, x, 1)
+		feeds := map[tf.Output]*tf.Tensor{s.Training(): mustTensor(t, true)}
+		sess := newTestSession(t, s)
+		fetched, err := sess.Run(feeds, []tf.Output{y, noisy}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return []interface{}{fetched[0].Value(), fetched[1].Value()}
+	}
+	if first, second := run(), run(); !reflect.DeepEqual(first, second) {
+		t.Errorf("Got different random values %v and %v with the same seed", first, second)
+	}
+}
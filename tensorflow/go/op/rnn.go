@@ -0,0 +1,169 @@
+package op
+
+import (
+	"fmt"
+
+	tf "github.com/hdu-hh/tensorflow/tensorflow/go"
+)
+
+// RNNOpts are the options of the recurrent layers [SimpleRNN], [LSTM] and
+// [GRU]. The layers are unrolled statically over the time steps of their
+// inputs, which therefore need a known sequence length. Unlike loops with
+// [While], the unrolled steps are differentiable with [Gradients], so the
+// layers can be trained with the optimizers.
+type RNNOpts struct {
+	// Mask marks the valid time steps of variable-length sequences.
+	// It has the shape [batch, time] and the data type of the input, with
+	// 1 for valid steps and 0 for padded steps. The state is carried over
+	// padded steps and their outputs are zeros. If Mask.Op is nil then all
+	// steps are valid.
+	Mask tf.Output
+
+	// ReturnSequences selects to return the outputs of all time steps with
+	// the shape [batch, time, units] instead of the output of the final
+	// state with the shape [batch, units].
+	ReturnSequences bool
+
+	// Reverse processes the sequences backwards. The outputs of all time
+	// steps are returned in their original order nevertheless.
+	Reverse bool
+
+	// Tags of the kernels and biases like for [Linear] and [Bias].
+	Tags []VarTag
+}
+
+// RecurrentLayer is the signature of the recurrent layer builders with their
+// number of units bound, e.g. for [Bidirectional].
+type RecurrentLayer func(s *Scope, x tf.Output, opts RNNOpts) tf.Output
+
+// recurrentCell computes the outputs and the new states of a time step
+// from its input and the previous states.
+type recurrentCell func(s *Scope, x tf.Output, states []tf.Output) (y tf.Output, newStates []tf.Output)
+
+// SimpleRNN implements a fully connected recurrent layer for inputs of the
+// shape [batch, time, features], computing h' = act(x*W + h*U + b).
+// If act is nil then Tanh is used.
+func SimpleRNN(s *Scope, x tf.Output, units int, act ActFunc, opts RNNOpts) tf.Output {
+	if act == nil {
+		act = Tanh
+	}
+	u := int64(units)
+	w := s.kernelVariable(tf.MakeShape(x.Shape().Size(-1), u), x.DataType(), opts.Tags)
+	r := s.kernelVariable(tf.MakeShape(u, u), x.DataType(), opts.Tags)
+	b := s.biasVariable(u, x.DataType(), opts.Tags)
+	cell := func(s *Scope, x tf.Output, states []tf.Output) (tf.Output, []tf.Output) {
+		h := act(s, BiasAdd(s, Add(s, MatMul(s, x, w), MatMul(s, states[0], r)), b))
+		return h, []tf.Output{h}
+	}
+	return s.recurrent(x, units, 1, cell, opts)
+}
+
+// LSTM implements a long short-term memory layer for inputs of the shape
+// [batch, time, features] with the input, forget, cell and output gates
+// (see https://www.bioinf.jku.at/publications/older/2604.pdf).
+func LSTM(s *Scope, x tf.Output, units int, opts RNNOpts) tf.Output {
+	u := int64(units)
+	w := s.kernelVariable(tf.MakeShape(x.Shape().Size(-1), 4*u), x.DataType(), opts.Tags)
+	r := s.kernelVariable(tf.MakeShape(u, 4*u), x.DataType(), opts.Tags)
+	b := s.biasVariable(4*u, x.DataType(), opts.Tags)
+	axis1 := Const(s, int32(1))
+	cell := func(s *Scope, x tf.Output, states []tf.Output) (tf.Output, []tf.Output) {
+		h, c := states[0], states[1]
+		z := BiasAdd(s, Add(s, MatMul(s, x, w), MatMul(s, h, r)), b)
+		gates := Split(s, axis1, z, 4)
+		i, f, g, o := Sigmoid(s, gates[0]), Sigmoid(s, gates[1]), Tanh(s, gates[2]), Sigmoid(s, gates[3])
+		c = Add(s, Mul(s, f, c), Mul(s, i, g))
+		h = Mul(s, o, Tanh(s, c))
+		return h, []tf.Output{h, c}
+	}
+	return s.recurrent(x, units, 2, cell, opts)
+}
+
+// GRU implements a gated recurrent unit layer for inputs of the shape
+// [batch, time, features] with the update and reset gates, which are applied
+// before the recurrent projection (see https://arxiv.org/abs/1406.1078).
+func GRU(s *Scope, x tf.Output, units int, opts RNNOpts) tf.Output {
+	u := int64(units)
+	w := s.kernelVariable(tf.MakeShape(x.Shape().Size(-1), 3*u), x.DataType(), opts.Tags)
+	rGates := s.kernelVariable(tf.MakeShape(u, 2*u), x.DataType(), opts.Tags)
+	rCand := s.kernelVariable(tf.MakeShape(u, u), x.DataType(), opts.Tags)
+	b := s.biasVariable(3*u, x.DataType(), opts.Tags)
+	axis1 := Const(s, int32(1))
+	cell := func(s *Scope, x tf.Output, states []tf.Output) (tf.Output, []tf.Output) {
+		h := states[0]
+		xs := Split(s, axis1, BiasAdd(s, MatMul(s, x, w), b), 3)
+		hs := Split(s, axis1, MatMul(s, h, rGates), 2)
+		z := Sigmoid(s, Add(s, xs[0], hs[0]))
+		r := Sigmoid(s, Add(s, xs[1], hs[1]))
+		cand := Tanh(s, Add(s, xs[2], MatMul(s, Mul(s, r, h), rCand)))
+		h = Add(s, cand, Mul(s, z, Sub(s, h, cand))) // z*h + (1-z)*cand
+		return h, []tf.Output{h}
+	}
+	return s.recurrent(x, units, 1, cell, opts)
+}
+
+// Bidirectional runs the recurrent layer forwards and backwards over the
+// sequences and concatenates both outputs along the last axis. E.g.:
+//
+//	lstm := func(s *Scope, x tf.Output, opts RNNOpts) tf.Output { return LSTM(s, x, 32, opts) }
+//	y := Bidirectional(s, x, lstm, RNNOpts{ReturnSequences: true})
+func Bidirectional(s *Scope, x tf.Output, layer RecurrentLayer, opts RNNOpts) tf.Output {
+	opts.Reverse = false
+	forward := layer(s.SubScope("forward"), x, opts)
+	opts.Reverse = true
+	backward := layer(s.SubScope("backward"), x, opts)
+	return ConcatV2(s, []tf.Output{forward, backward}, Const(s, int32(-1)))
+}
+
+// recurrent unrolls the cell over the time steps of the input x.
+func (s *Scope) recurrent(x tf.Output, units, numStates int, cell recurrentCell, opts RNNOpts) tf.Output {
+	steps := x.Shape().Size(1)
+	if x.Shape().NumDimensions() != 3 || steps <= 0 {
+		s.UpdateErr("recurrent", fmt.Errorf("recurrent layers need inputs of the shape [batch, time, features] with a known time, got %v", x.Shape()))
+		return tf.Output{}
+	}
+	xs := Unpack(s, x, steps, UnpackAxis(1))
+	var masks []tf.Output
+	if opts.Mask.Op != nil {
+		masks = Unpack(s, opts.Mask, steps, UnpackAxis(1))
+	}
+
+	// the initial states are zeros of the shape [batch, units]
+	axis0 := Const(s, int32(0))
+	batch := s.dynamicDims(x, 0, 1)
+	dims := ConcatV2(s, []tf.Output{batch, Const(s, []int32{int32(units)})}, axis0)
+	zeros := Fill(s, dims, Cast(s, Const(s, float32(0)), x.DataType()))
+	states := make([]tf.Output, numStates)
+	for i := range states {
+		states[i] = zeros
+	}
+
+	ys := make([]tf.Output, steps)
+	for i := range ys {
+		t := i
+		if opts.Reverse {
+			t = len(ys) - 1 - i
+		}
+		y, newStates := cell(s, xs[t], states)
+		if masks != nil { // keep the states of padded steps
+			m := ExpandDims(s, masks[t], Const(s, int32(1)))
+			notM := Sub(s, OnesLike(s, m), m)
+			for j, state := range newStates {
+				newStates[j] = Add(s, Mul(s, m, state), Mul(s, notM, states[j]))
+			}
+			y = Mul(s, m, y)
+		}
+		ys[t], states = y, newStates
+	}
+	if !opts.ReturnSequences {
+		return states[0]
+	}
+	return Pack(s, ys, PackAxis(1))
+}
+
+// dynamicDims returns the sizes of the dimensions begin...end-1 of x as int32
+// tensor, which keeps them known to the shape inference if possible.
+func (s *Scope) dynamicDims(x tf.Output, begin, end int32) tf.Output {
+	shape := Shape(s, StopGradient(s, x), ShapeOutType(tf.Int32))
+	return StridedSlice(s, shape, Const(s, []int32{begin}), Const(s, []int32{end}), Const(s, []int32{1}))
+}
@@ -0,0 +1,92 @@
+package op
+
+import (
+	"reflect"
+	"testing"
+
+	tf "github.com/hdu-hh/tensorflow/tensorflow/go"
+)
+
+func TestRecurrentLayers(t *testing.T) {
+	const B, T, F, U = 2, 3, 4, 5
+	s := NewScope()
+	x := Placeholder(s, tf.Float, PlaceholderShape(tf.MakeShape(-1, T, F)))
+	mask := Placeholder(s, tf.Float, PlaceholderShape(tf.MakeShape(-1, T)))
+	gru := func(s *Scope, x tf.Output, opts RNNOpts) tf.Output { return GRU(s, x, U, opts) }
+	tests := []struct {
+		name string
+		y    tf.Output
+		want []int64
+	}{
+		{"SimpleRNN", SimpleRNN(s, x, U, nil, RNNOpts{}), []int64{B, U}},
+		{"LSTM", LSTM(s, x, U, RNNOpts{}), []int64{B, U}},
+		{"LSTM/sequences", LSTM(s, x, U, RNNOpts{ReturnSequences: true}), []int64{B, T, U}},
+		{"GRU/reverse", GRU(s, x, U, RNNOpts{Reverse: true}), []int64{B, U}},
+		{"Bidirectional", Bidirectional(s, x, gru, RNNOpts{ReturnSequences: true}), []int64{B, T, 2 * U}},
+	}
+	masked := LSTM(s, x, U, RNNOpts{Mask: mask, ReturnSequences: true})
+	maskedFinal := LSTM(s, x, U, RNNOpts{Mask: mask})
+	sess := newTestSession(t, s)
+	xValue := make([][][]float32, B)
+	for b := range xValue {
+		xValue[b] = make([][]float32, T)
+		for i := range xValue[b] {
+			xValue[b][i] = []float32{1, -1, 0.5, float32(b + i)}
+		}
+	}
+	feeds := map[tf.Output]*tf.Tensor{
+		x:    mustTensor(t, xValue),
+		mask: mustTensor(t, [][]float32{{1, 1, 1}, {1, 0, 0}}),
+	}
+	for _, test := range tests {
+		fetched, err := sess.Run(feeds, []tf.Output{test.y}, nil)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if got := fetched[0].Shape(); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got shape %v, want %v", test.name, got, test.want)
+		}
+	}
+
+	// padded steps output zeros and keep the state
+	fetched, err := sess.Run(feeds, []tf.Output{masked}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seq := fetched[0].Value().([][][]float32)
+	for _, step := range seq[1][1:] {
+		if !reflect.DeepEqual(step, make([]float32, U)) {
+			t.Errorf("Got output %v for a padded step, want zeros", step)
+		}
+	}
+	if reflect.DeepEqual(seq[1][0], make([]float32, U)) {
+		t.Errorf("Got zeros for a valid step")
+	}
+	fetched, err = sess.Run(feeds, []tf.Output{maskedFinal}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fetched[0].Shape(); !reflect.DeepEqual(got, []int64{B, U}) {
+		t.Errorf("Got final state shape %v", got)
+	}
+}
+
+func TestRecurrentTraining(t *testing.T) {
+	s := NewScope()
+	// learn to sum up the sequences
+	x := Const(s, [][][]float32{{{1}, {2}, {0}}, {{0}, {1}, {1}}, {{2}, {2}, {2}}, {{1}, {0}, {0}}})
+	want := Const(s, [][]float32{{3}, {2}, {6}, {1}})
+	y := Linear(s, GRU(s, x, 8, RNNOpts{}), 1)
+	loss := Mean(s, Flatten(s, Square(s, Sub(s, y, want))), Const(s, int32(0)))
+	opti := OptimizerAdam(s, []tf.Output{loss}, 1e-2, 1e-3, 0.9, 0.999)
+	sess := newTestSession(t, s)
+	fetched := opti.Step(sess, nil, []tf.Output{loss}, nil)
+	firstLoss := fetched[0].Value().(float32)
+	for step := 0; step < 300; step++ {
+		fetched = opti.Step(sess, nil, []tf.Output{loss}, nil)
+	}
+	if finalLoss := fetched[0].Value().(float32); finalLoss >= 0.5*firstLoss {
+		t.Errorf("loss only improved from %.3f to %.3f", firstLoss, finalLoss)
+	}
+}
@@ -0,0 +1,252 @@
+package op
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	tf "github.com/hdu-hh/tensorflow/tensorflow/go"
+)
+
+// checkpointStateFile is the name of the file listing the checkpoints
+// of a directory, which is compatible with tf.train.CheckpointState.
+const checkpointStateFile = "checkpoint"
+
+// Saver saves the variables of a scope to tensor bundle checkpoints
+// (a prefix.index file and prefix.data-* files) and restores them.
+//
+// The variables are saved with the names of their operations, so that they
+// can be restored into a fresh graph built by the same code.
+type Saver struct {
+	// MaxToKeep is the number of recent checkpoints to keep.
+	// Older checkpoints are deleted. If zero, all checkpoints are kept.
+	MaxToKeep int
+
+	names       []string
+	prefix      tf.Output
+	saveOp      *tf.Operation
+	restoreOp   *tf.Operation
+	dir         string   // the directory of the kept checkpoints
+	checkpoints []string // the kept checkpoints, oldest first
+}
+
+// NewSaver creates a Saver for the variables in the scope which have any of
+// the requested tags. If no tags are provided then all tagged variables are
+// saved, which includes the optimizer states like the Adam moments.
+// By default the 5 most recent checkpoints are kept.
+func NewSaver(s *Scope, tags ...VarTag) *Saver {
+	vars := s.taggedVariables(tags...)
+	if len(vars) == 0 {
+		panic(fmt.Errorf("no variables to save found for tags: %v", tags))
+	}
+	s = s.SubScope("saver")
+	names := make([]string, len(vars))
+	slices := make([]string, len(vars))
+	dtypes := make([]tf.DataType, len(vars))
+	for i, v := range vars {
+		names[i] = v.Op.Name()
+		dtypes[i] = v.DataType().DeRef()
+	}
+	prefix := Placeholder(s, tf.String, PlaceholderShape(tf.ScalarShape()))
+	namesConst := Const(s, names)
+	slicesConst := Const(s, slices)
+	saveOp := SaveV2(s, prefix, namesConst, slicesConst, vars)
+	restored := RestoreV2(s, prefix, namesConst, slicesConst, dtypes)
+	assignOps := make([]*tf.Operation, len(vars))
+	for i, v := range vars {
+		assignOps[i] = Assign(s, v, restored[i]).Op
+	}
+	return &Saver{
+		MaxToKeep: 5,
+		names:     names,
+		prefix:    prefix,
+		saveOp:    saveOp,
+		restoreOp: NoOp(s.WithControlDependencies(assignOps...)),
+	}
+}
+
+// taggedVariables returns the variables with any of the tags sorted by name.
+// If no tags are provided then all tagged variables are returned.
+func (s *Scope) taggedVariables(tags ...VarTag) []tf.Output {
+	if len(tags) == 0 {
+		for tag := range *s.outTagMap {
+			tags = append(tags, tag)
+		}
+	}
+	byName := map[string]tf.Output{}
+	for _, tag := range tags {
+		for _, x := range (*s.outTagMap)[tag] {
+			if x.Op.Type() == "Assign" { // initialized by an assignment
+				x = tf.Consumer{Op: x.Op, Index: 0}.Producer()
+			}
+			byName[x.Op.Name()] = x
+		}
+	}
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	vars := make([]tf.Output, len(names))
+	for i, name := range names {
+		vars[i] = byName[name]
+	}
+	return vars
+}
+
+// VariableNames returns the names under which the variables are saved.
+func (sv *Saver) VariableNames() []string {
+	return append([]string(nil), sv.names...)
+}
+
+// Save saves the variables to a checkpoint in the session. The path of
+// the checkpoint is the prefix followed by "-step" unless step is negative.
+// It also updates the "checkpoint" file in the directory of the checkpoint
+// and deletes the checkpoints exceeding MaxToKeep, including those listed
+// in the "checkpoint" file by earlier savers.
+func (sv *Saver) Save(sess *tf.Session, prefix string, step int) (string, error) {
+	path := prefix
+	if step >= 0 {
+		path = fmt.Sprint(prefix, "-", step)
+	}
+	path = filepath.Clean(path)
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	if dir != sv.dir { // resume the checkpoints of the directory
+		_, checkpoints, err := readCheckpointState(dir)
+		if err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+		sv.dir, sv.checkpoints = dir, checkpoints
+	}
+	feeds, err := sv.feeds(path)
+	if err != nil {
+		return "", err
+	}
+	if _, err := sess.Run(feeds, nil, []*tf.Operation{sv.saveOp}); err != nil {
+		return "", fmt.Errorf("failed to save checkpoint %q: %w", path, err)
+	}
+
+	for i, p := range sv.checkpoints {
+		if p == path {
+			sv.checkpoints = append(sv.checkpoints[:i], sv.checkpoints[i+1:]...)
+			break
+		}
+	}
+	sv.checkpoints = append(sv.checkpoints, path)
+	if sv.MaxToKeep > 0 {
+		for len(sv.checkpoints) > sv.MaxToKeep {
+			if err := deleteCheckpoint(sv.checkpoints[0]); err != nil {
+				return "", err
+			}
+			sv.checkpoints = sv.checkpoints[1:]
+		}
+	}
+	return path, writeCheckpointState(dir, sv.checkpoints)
+}
+
+// Restore restores the variables from the checkpoint in the session.
+func (sv *Saver) Restore(sess *tf.Session, path string) error {
+	feeds, err := sv.feeds(path)
+	if err != nil {
+		return err
+	}
+	if _, err := sess.Run(feeds, nil, []*tf.Operation{sv.restoreOp}); err != nil {
+		return fmt.Errorf("failed to restore checkpoint %q: %w", path, err)
+	}
+	return nil
+}
+
+func (sv *Saver) feeds(path string) (tf.FeedMap, error) {
+	prefix, err := tf.NewTensor(path)
+	if err != nil {
+		return nil, err
+	}
+	return tf.FeedMap{sv.prefix: prefix}, nil
+}
+
+// deleteCheckpoint deletes the files of the checkpoint.
+func deleteCheckpoint(path string) error {
+	files, err := filepath.Glob(path + ".data-?????-of-?????")
+	if err != nil {
+		return err
+	}
+	for _, name := range append(files, path+".index") {
+		if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeCheckpointState writes the "checkpoint" file of the directory in the
+// text format of tf.train.CheckpointState with the latest checkpoint last.
+func writeCheckpointState(dir string, checkpoints []string) error {
+	relPath := func(path string) string {
+		if filepath.Dir(path) == dir {
+			return filepath.Base(path)
+		}
+		return path
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "model_checkpoint_path: %s\n", strconv.Quote(relPath(checkpoints[len(checkpoints)-1])))
+	for _, path := range checkpoints {
+		fmt.Fprintf(&b, "all_model_checkpoint_paths: %s\n", strconv.Quote(relPath(path)))
+	}
+	tmp := filepath.Join(dir, checkpointStateFile+".tmp")
+	if err := os.WriteFile(tmp, []byte(b.String()), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, checkpointStateFile))
+}
+
+// LatestCheckpoint returns the path of the latest checkpoint listed in the
+// "checkpoint" file of the directory.
+func LatestCheckpoint(dir string) (string, error) {
+	latest, _, err := readCheckpointState(dir)
+	if err != nil {
+		return "", err
+	}
+	if latest == "" {
+		return "", fmt.Errorf("no checkpoint found in %q", dir)
+	}
+	return latest, nil
+}
+
+// readCheckpointState reads the latest and all checkpoints listed in the
+// "checkpoint" file of the directory with paths relative to the directory
+// resolved.
+func readCheckpointState(dir string) (latest string, all []string, err error) {
+	f, err := os.Open(filepath.Join(dir, checkpointStateFile))
+	if err != nil {
+		return "", nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		key = strings.TrimSpace(key)
+		if !ok || key != "model_checkpoint_path" && key != "all_model_checkpoint_paths" {
+			continue
+		}
+		path, err := strconv.Unquote(strings.TrimSpace(value))
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid checkpoint state %q: %w", scanner.Text(), err)
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		if key == "model_checkpoint_path" {
+			latest = path
+		} else {
+			all = append(all, path)
+		}
+	}
+	return latest, all, scanner.Err()
+}
@@ -0,0 +1,130 @@
+package op
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	tf "github.com/hdu-hh/tensorflow/tensorflow/go"
+)
+
+// saverTestModel builds a model with an optimizer and a saver for its state.
+func saverTestModel(t *testing.T) (*tf.Session, *Saver, Optimizer, []tf.Output, *tf.Operation) {
+	s := NewScope()
+	x := Const(s, [][]float32{{1, 2}, {3, 4}})
+	y := MLP(s, x, 2, Tanh)
+	loss := Mean(s, Flatten(s, Square(s, y)), Const(s, int32(0)))
+	opti := OptimizerAdam(s, []tf.Output{loss}, 1e-3, 1e-3, 0.9, 0.999)
+	saver := NewSaver(s)
+	vars := s.taggedVariables()
+	initOp := s.GetInitOp()
+	graph, err := s.Finalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess, err := tf.NewSession(graph, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sess, saver, opti, vars, initOp
+}
+
+func TestSaver(t *testing.T) {
+	dir := t.TempDir()
+	sess, saver, opti, vars, initOp := saverTestModel(t)
+	// weights, biases and the two Adam moments of each
+	if got := len(saver.VariableNames()); got != 6 {
+		t.Errorf("Got %d variables %v, want 6", got, saver.VariableNames())
+	}
+	if _, err := sess.Run(nil, nil, []*tf.Operation{initOp}); err != nil {
+		t.Fatal(err)
+	}
+	saver.MaxToKeep = 2
+	prefix := filepath.Join(dir, "model.ckpt")
+	var paths []string
+	for step := 1; step <= 3; step++ {
+		opti.Step(sess, nil, nil, nil)
+		path, err := saver.Save(sess, prefix, step)
+		if err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	want, err := sess.Run(nil, vars, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// only the latest checkpoints are kept
+	if _, err := os.Stat(paths[0] + ".index"); !os.IsNotExist(err) {
+		t.Errorf("Checkpoint %q was not deleted", paths[0])
+	}
+	for _, path := range paths[1:] {
+		if _, err := os.Stat(path + ".index"); err != nil {
+			t.Error(err)
+		}
+		if _, err := os.Stat(path + ".data-00000-of-00001"); err != nil {
+			t.Error(err)
+		}
+	}
+	latest, err := LatestCheckpoint(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest != paths[2] {
+		t.Errorf("Got latest checkpoint %q, want %q", latest, paths[2])
+	}
+
+	// restore into a fresh graph
+	sess2, saver2, _, vars2, _ := saverTestModel(t)
+	if err := saver2.Restore(sess2, latest); err != nil {
+		t.Fatal(err)
+	}
+	got, err := sess2.Run(nil, vars2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i].Value(), want[i].Value()) {
+			t.Errorf("Variable %q: got %v, want %v", vars2[i].Op.Name(), got[i].Value(), want[i].Value())
+		}
+	}
+	if err := saver2.Restore(sess2, paths[0]); err == nil {
+		t.Errorf("Restoring a deleted checkpoint did not fail")
+	}
+}
+
+func TestSaverResume(t *testing.T) {
+	dir := t.TempDir()
+	prefix := filepath.Join(dir, "model.ckpt")
+	var paths []string
+	save := func(steps ...int) {
+		t.Helper()
+		sess, saver, _, _, initOp := saverTestModel(t)
+		if _, err := sess.Run(nil, nil, []*tf.Operation{initOp}); err != nil {
+			t.Fatal(err)
+		}
+		saver.MaxToKeep = 2
+		for _, step := range steps {
+			path, err := saver.Save(sess, prefix, step)
+			if err != nil {
+				t.Fatal(err)
+			}
+			paths = append(paths, path)
+		}
+	}
+	save(1, 2)
+	// a new saver continues with the checkpoints of the directory
+	save(3)
+	if _, err := os.Stat(paths[0] + ".index"); !os.IsNotExist(err) {
+		t.Errorf("Checkpoint %q of the earlier saver was not deleted", paths[0])
+	}
+	latest, all, err := readCheckpointState(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest != paths[2] || !reflect.DeepEqual(all, paths[1:]) {
+		t.Errorf("Got checkpoints %v with latest %q, want %v", all, latest, paths[1:])
+	}
+}
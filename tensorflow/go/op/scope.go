@@ -39,6 +39,8 @@ type Scope struct {
 	controlDependencies []*tf.Operation
 	device              string
 	outTagMap           *outTagMap
+	eager               *eagerState
+	phase               *phaseState
 	err                 *scopeErr
 }
 
@@ -52,22 +54,26 @@ type scopeErr struct {
 
 // NewScope creates a Scope initialized with an empty graph
 func NewScope() *Scope {
-	return &Scope{
+	s := &Scope{
 		graph:     tf.NewGraph(),
 		namemap:   &opNameMap{},
 		outTagMap: &outTagMap{},
 		err:       new(scopeErr),
 	}
+	s.phase = &phaseState{root: s}
+	return s
 }
 
 // NewScopeWithGraph creates a Scope initialized with the graph thats passed in
 func NewScopeWithGraph(graph *tf.Graph) *Scope {
-	return &Scope{
+	s := &Scope{
 		graph:     graph,
 		namemap:   &opNameMap{},
 		outTagMap: &outTagMap{},
 		err:       new(scopeErr),
 	}
+	s.phase = &phaseState{root: s}
+	return s
 }
 
 // Finalize returns the [tf.Graph] on which this scope operates on and renders s
@@ -102,6 +108,10 @@ func (s *Scope) AddOperation(args tf.OpSpec) *tf.Operation {
 	op, err := s.graph.AddOperation(args)
 	if err != nil {
 		s.UpdateErr(args.Type, err)
+	} else if s.eager != nil {
+		if err := s.eager.execute(op, args); err != nil {
+			s.UpdateErr(args.Type, err)
+		}
 	}
 	return op
 }
@@ -120,6 +130,8 @@ func (s *Scope) SubScope(namespace string) *Scope {
 		namespace:           namespace,
 		controlDependencies: s.controlDependencies,
 		outTagMap:           s.outTagMap,
+		eager:               s.eager,
+		phase:               s.phase,
 		device:              s.device,
 		err:                 s.err,
 	}
@@ -144,6 +156,8 @@ func (s *Scope) WithControlDependencies(ops ...*tf.Operation) *Scope {
 		namespace:           s.namespace,
 		controlDependencies: deps,
 		outTagMap:           s.outTagMap,
+		eager:               s.eager,
+		phase:               s.phase,
 		device:              s.device,
 		err:                 s.err,
 	}
@@ -163,6 +177,8 @@ func (s *Scope) WithDevice(device string) *Scope {
 		namespace:           s.namespace,
 		controlDependencies: s.controlDependencies,
 		outTagMap:           s.outTagMap,
+		eager:               s.eager,
+		phase:               s.phase,
 		device:              device,
 		err:                 s.err,
 	}
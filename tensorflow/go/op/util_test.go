@@ -0,0 +1,64 @@
+package op
+
+import (
+	"math"
+	"testing"
+
+	tf "github.com/hdu-hh/tensorflow/tensorflow/go"
+)
+
+// newTestSession finalizes the scope and returns a session for its graph
+// in which the variables of the scope are initialized.
+func newTestSession(t *testing.T, s *Scope) *tf.Session {
+	t.Helper()
+	initOp := s.GetInitOp()
+	graph, err := s.Finalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess, err := tf.NewSession(graph, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sess.Run(nil, nil, []*tf.Operation{initOp}); err != nil {
+		t.Fatal(err)
+	}
+	return sess
+}
+
+// mustTensor creates a tensor from the value.
+func mustTensor(t *testing.T, value interface{}) *tf.Tensor {
+	t.Helper()
+	tensor, err := tf.NewTensor(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tensor
+}
+
+// zeros creates a float32 tensor of zeros with the shape.
+func zeros(t *testing.T, shape ...int64) *tf.Tensor {
+	t.Helper()
+	size := int64(1)
+	for _, d := range shape {
+		size *= d
+	}
+	tensor, err := tf.NewTensor(make([]float32, size))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tensor.Reshape(shape); err != nil {
+		t.Fatal(err)
+	}
+	return tensor
+}
+
+// approxEqual reports whether the values differ by at most 1e-5.
+func approxEqual(a, b []float32) bool {
+	for i := range a {
+		if math.Abs(float64(a[i]-b[i])) > 1e-5 {
+			return false
+		}
+	}
+	return true
+}
@@ -15,7 +15,7 @@ const (
 	TagInitUniform       VarTag = "TagInitUniform"       // limits=0...1
 	TagInitEpsUniform    VarTag = "TagInitEpsNormal"     // mean=0, stddev=1e-4
 	TagInitTruncNormal   VarTag = "TagInitTruncNormal"   // mean=0, stddev=1
-	TagInitHeUniform     VarTag = "TagInitHeUniform"     // +-limit=sqrt(2/fan_in)
+	TagInitHeUniform     VarTag = "TagInitHeUniform"     // +-limit=sqrt(6/fan_in)
 	TagInitHeNormal      VarTag = "TagInitHeNormal"      // mean=0, stddev=sqrt(2/fan_in), +-limit=2*stddev
 	TagInitLecunUniform  VarTag = "TagInitLecunUniform"  // +-limit=sqrt(3/fan_in)
 	TagInitLecunNormal   VarTag = "TagInitLecunNormal"   // mean=0, stddev=sqrt(1/fan_in)
@@ -53,6 +53,16 @@ func addInitOp(s *Scope, x tf.Output, tag VarTag) *tf.Operation {
 	}
 	shape := xShape.(tf.Shape)
 	shapeConst := Const(s, shape.MustSlice32())
+	scalar := func(v float64) tf.Output { return Cast(s, Const(s, float32(v)), dtype) }
+	truncNormal := func() tf.Output {
+		seed, seed2 := s.randomSeeds()
+		return TruncatedNormal(s, shapeConst, dtype, TruncatedNormalSeed(seed), TruncatedNormalSeed2(seed2))
+	}
+	uniform := func(limit float64) tf.Output { // symmetric in -limit...+limit
+		y := s.randomUniform(shapeConst, dtype)
+		return Sub(s, Mul(s, y, scalar(2*limit)), scalar(limit))
+	}
+	fanIn, fanOut := fans(shape)
 	var y tf.Output
 	switch tag {
 	case TagInitZeros:
@@ -60,38 +70,50 @@ func addInitOp(s *Scope, x tf.Output, tag VarTag) *tf.Operation {
 	case TagInitOnes:
 		y = OnesLike(s, Empty(s, shapeConst, dtype))
 	case TagInitUniform:
-		y = RandomUniform(s, shapeConst, dtype)
+		y = s.randomUniform(shapeConst, dtype)
 	case TagInitEpsUniform:
-		y = RandomUniform(s, shapeConst, dtype)
-		y = Mul(s, y, Const(s, float32(1e-4)))
+		y = s.randomUniform(shapeConst, dtype)
+		y = Mul(s, y, scalar(1e-4))
 	case TagInitTruncNormal:
-		y = TruncatedNormal(s, shapeConst, dtype)
+		y = truncNormal()
 	case TagInitHeUniform:
-		f := math.Sqrt2 / float32(shape.Size(0))
-		y = RandomUniform(s, shapeConst, dtype)
-		y = Mul(s, y, Const(s, f))
+		y = uniform(math.Sqrt(6 / fanIn))
 	case TagInitHeNormal:
-		f := math.Sqrt2 / float32(shape.Size(0))
-		avg, std, low, high := Const(s, float32(0)), Const(s, f), Const(s, -2*f), Const(s, +2*f)
-		y = ParameterizedTruncatedNormal(s, shapeConst, avg, std, low, high)
+		f := math.Sqrt(2 / fanIn)
+		avg, std, low, high := scalar(0), scalar(f), scalar(-2*f), scalar(+2*f)
+		seed, seed2 := s.randomSeeds()
+		y = ParameterizedTruncatedNormal(s, shapeConst, avg, std, low, high,
+			ParameterizedTruncatedNormalSeed(seed), ParameterizedTruncatedNormalSeed2(seed2))
 	case TagInitLecunUniform:
-		f := 1.73 / float32(shape.Size(0))
-		y = RandomUniform(s, shapeConst, dtype)
-		y = Mul(s, y, Const(s, f))
+		y = uniform(math.Sqrt(3 / fanIn))
 	case TagInitLecunNormal:
-		f := 1 / float32(shape.Size(0))
-		y = TruncatedNormal(s, shapeConst, dtype) // std LeCun is not truncated
-		y = Mul(s, y, Const(s, f))
+		y = truncNormal() // std LeCun is not truncated
+		y = Mul(s, y, scalar(math.Sqrt(1/fanIn)))
 	case TagInitXavierUniform:
-		f := 2.45 / float32(shape.Size(0)+shape.Size(-1))
-		y = RandomUniform(s, shapeConst, dtype)
-		y = Mul(s, y, Const(s, f))
+		y = uniform(math.Sqrt(6 / (fanIn + fanOut)))
 	case TagInitXavierNormal:
-		f := math.Sqrt2 / float32(shape.Size(0)+shape.Size(-1))
-		y = TruncatedNormal(s, shapeConst, dtype) // std Xavier is not truncated
-		y = Mul(s, y, Const(s, f))
+		y = truncNormal() // std Xavier is not truncated
+		y = Mul(s, y, scalar(math.Sqrt(2/(fanIn+fanOut))))
 	default:
 		panic(fmt.Errorf("init tag %q not implemented yet", tag))
 	}
 	return Assign(s, x, y).Op
 }
+
+// fans returns the fan-in and fan-out of a variable with the shape used by the
+// initializers. Kernels of convolutions have the shape [spatial..., in, out],
+// so their receptive field multiplies the numbers of input and output channels.
+func fans(shape tf.Shape) (fanIn, fanOut float64) {
+	dims := shape.MustSlice()
+	switch len(dims) {
+	case 0:
+		return 1, 1
+	case 1:
+		return float64(dims[0]), float64(dims[0])
+	}
+	receptive := int64(1)
+	for _, size := range dims[:len(dims)-2] {
+		receptive *= size
+	}
+	return float64(receptive * dims[len(dims)-2]), float64(receptive * dims[len(dims)-1])
+}
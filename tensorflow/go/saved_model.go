@@ -17,11 +17,15 @@ limitations under the License.
 package tensorflow
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
+	"sync"
 	"unsafe"
 
 	"github.com/hdu-hh/tensorflow/tensorflow/go/pbs"
@@ -33,11 +37,32 @@ import (
 import "C"
 
 // SavedModel represents the contents of loaded SavedModel.
-// TODO(jhseu): Add and document metagraphdef when we pregenerate protobufs.
 type SavedModel struct {
 	Session    *Session
 	Graph      *Graph
 	Signatures map[string]Signature
+
+	// MetaGraphDef is the loaded MetaGraph, which the following
+	// fields are taken from.
+	MetaGraphDef *pbs.MetaGraphDef
+
+	// Assets maps the names of the tensors holding the file names of
+	// assets, like vocabularies of lookup tables, to the paths of the files.
+	Assets map[string]string
+
+	// SaverDef describes the operations which save and restore the variables.
+	SaverDef *pbs.SaverDef
+
+	// CollectionDefs are the collections of the MetaGraph by key, e.g. the
+	// initializer operations of tables in "table_initializer".
+	CollectionDefs map[string]*pbs.CollectionDef
+
+	// ObjectGraph describes the objects of models saved by TensorFlow 2, like
+	// variables and concrete functions. It is nil for TensorFlow 1 models.
+	ObjectGraph *pbs.SavedObjectGraph
+
+	runnersMu sync.Mutex
+	runners   map[string]*SignatureRunner // the runners by signature key
 }
 
 // LoadSavedModel creates a new [SavedModel] from a model previously
@@ -50,24 +75,38 @@ type SavedModel struct {
 //
 // Tags per graph can be listed using the [ListSavedModelDetails] function.
 //
-// The tensorflow package currently does not have the ability to export a model
-// to a directory from Go. This function thus currently targets loading models
-// exported in other languages, such as using tf.saved_model.builder in Python.
-// See:
+// Models can be exported from Go with [SaveSavedModel] or in other
+// languages, such as using tf.saved_model.builder in Python. See:
 // https://www.tensorflow.org/code/tensorflow/python/saved_model/
 func LoadSavedModel(exportDir string, tags []string, options *SessionOptions) (*SavedModel, error) {
+	return LoadSavedModelWithOptions(exportDir, tags, options, nil)
+}
+
+// LoadSavedModelWithOptions is like [LoadSavedModel] but also accepts the
+// [pbs.RunOptions] for restoring the variables and running the initializers.
+// runOptions may be nil to use the default options.
+func LoadSavedModelWithOptions(exportDir string, tags []string, options *SessionOptions, runOptions *pbs.RunOptions) (*SavedModel, error) {
 	status := newStatus()
 	cOpt, doneOpt, err := options.c()
 	defer doneOpt()
 	if err != nil {
 		return nil, err
 	}
-	cExportDir := C.CString(exportDir)
 	if len(tags) == 0 {
 		return nil, fmt.Errorf("empty tags are not allowed." +
 			" Use the ListSavedModelDetails() function to list tags per graph",
 		)
 	}
+	var cRunOptions *C.TF_Buffer
+	if runOptions != nil {
+		buf, err := proto.Marshal(runOptions)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RunOptions: %w", err)
+		}
+		cRunOptions = newBufferFromSlice(buf)
+		defer C.TF_DeleteBuffer(cRunOptions)
+	}
+	cExportDir := C.CString(exportDir)
 	cTags := make([]*C.char, len(tags))
 	for i := range tags {
 		cTags[i] = C.CString(tags[i])
@@ -75,8 +114,7 @@ func LoadSavedModel(exportDir string, tags []string, options *SessionOptions) (*
 	graph := NewGraph()
 	metaGraphDefBuf := C.TF_NewBuffer()
 	defer C.TF_DeleteBuffer(metaGraphDefBuf)
-	// TODO(jhseu): Add support for run_options and meta_graph_def.
-	cSess := C.TF_LoadSessionFromSavedModel(cOpt, nil, cExportDir, (**C.char)(unsafe.Pointer(&cTags[0])), C.int(len(cTags)), graph.c, metaGraphDefBuf, status.c)
+	cSess := C.TF_LoadSessionFromSavedModel(cOpt, cRunOptions, cExportDir, (**C.char)(unsafe.Pointer(&cTags[0])), C.int(len(cTags)), graph.c, metaGraphDefBuf, status.c)
 	for i := range cTags {
 		C.free(unsafe.Pointer(cTags[i]))
 	}
@@ -100,9 +138,23 @@ func LoadSavedModel(exportDir string, tags []string, options *SessionOptions) (*
 	if err := status.Err(); err != nil {
 		return nil, err
 	}
+	assets := make(map[string]string)
+	for _, asset := range metaGraphDef.GetAssetFileDef() {
+		assets[asset.GetTensorInfo().GetName()] = filepath.Join(exportDir, "assets", asset.GetFilename())
+	}
+
 	s := &Session{c: cSess}
 	runtime.SetFinalizer(s, func(s *Session) { s.Close() })
-	return &SavedModel{Session: s, Graph: graph, Signatures: signatures}, nil
+	return &SavedModel{
+		Session:        s,
+		Graph:          graph,
+		Signatures:     signatures,
+		MetaGraphDef:   metaGraphDef,
+		Assets:         assets,
+		SaverDef:       metaGraphDef.GetSaverDef(),
+		CollectionDefs: metaGraphDef.GetCollectionDef(),
+		ObjectGraph:    metaGraphDef.GetObjectGraphDef(),
+	}, nil
 }
 
 func generateSignatures(pb map[string]*pbs.SignatureDef) map[string]Signature {
@@ -135,3 +187,278 @@ func loadSavedModelProto(path string) (model *pbs.SavedModel, err error) {
 	}
 	return
 }
+
+// SaveSavedModel exports the graph and the values of its variables in the
+// session as a SavedModel to the directory exportDir, which must not contain
+// a SavedModel yet. The exported MetaGraph is identified by the tags,
+// usually "serve" for serving, and the signatures describe its computations,
+// usually with the key "serving_default".
+//
+// The assets map the names of string tensors in the graph, usually constants
+// used by the initializers of lookup tables, to the asset files, which are
+// copied to the "assets" subdirectory. When the SavedModel is loaded, the
+// tensors are fed with the paths of the copied files while the variables
+// are restored and while its initialization operation runs, which is given
+// by the output "__saved_model_init_op" of the signature with the same key.
+// The file names of the assets must be unique.
+//
+// The variables (VariableV2 and VarHandleOp operations) are saved with the
+// names of their operations, like by tf.compat.v1.train.Saver in Python. For
+// this purpose, operations to save and restore them are added to the graph.
+func SaveSavedModel(exportDir string, graph *Graph, session *Session, tags []string, signatures map[string]Signature, assets map[string]string) error {
+	if len(tags) == 0 {
+		return fmt.Errorf("empty tags are not allowed")
+	}
+	modelPath := filepath.Join(exportDir, "saved_model.pb")
+	if _, err := os.Stat(modelPath); err == nil {
+		return fmt.Errorf("SavedModel already exists in %q", exportDir)
+	}
+	if err := os.MkdirAll(exportDir, 0o755); err != nil {
+		return err
+	}
+
+	var assetDefs []*pbs.AssetFileDef
+	if len(assets) > 0 {
+		var err error
+		if assetDefs, err = saveAssets(exportDir, graph, assets); err != nil {
+			return err
+		}
+	}
+	saverDef, err := saveVariables(exportDir, graph, session)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if _, err := graph.WriteTo(&buf); err != nil {
+		return err
+	}
+	graphDef := new(pbs.GraphDef)
+	if err := proto.Unmarshal(buf.Bytes(), graphDef); err != nil {
+		return err
+	}
+	metaGraph := &pbs.MetaGraphDef{
+		MetaInfoDef: &pbs.MetaGraphDef_MetaInfoDef{
+			Tags:              tags,
+			TensorflowVersion: Version(),
+		},
+		GraphDef:     graphDef,
+		SaverDef:     saverDef,
+		SignatureDef: make(map[string]*pbs.SignatureDef),
+		AssetFileDef: assetDefs,
+	}
+	for key, signature := range signatures {
+		metaGraph.SignatureDef[key] = signatureDefToProto(signature)
+	}
+	model := &pbs.SavedModel{SavedModelSchemaVersion: 1, MetaGraphs: []*pbs.MetaGraphDef{metaGraph}}
+	b, err := proto.Marshal(model)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(modelPath, b, 0o644)
+}
+
+// saveVariables adds operations to save and restore the variables of the
+// graph and saves them to the "variables" subdirectory. It returns nil if
+// the graph has no variables.
+func saveVariables(exportDir string, graph *Graph, session *Session) (*pbs.SaverDef, error) {
+	var vars []*Operation
+	for _, op := range graph.Operations() {
+		if op.Type() == "VariableV2" || op.Type() == "VarHandleOp" {
+			op := op
+			vars = append(vars, &op)
+		}
+	}
+	if len(vars) == 0 {
+		return nil, nil
+	}
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name() < vars[j].Name() })
+
+	prefix := "save"
+	for i := 1; graph.Operation(prefix+"/Const") != nil; i++ {
+		prefix = fmt.Sprintf("save_%d", i)
+	}
+	names := make([]string, len(vars))
+	values := make(OutputList, len(vars))
+	dtypes := make([]DataType, len(vars))
+	for i, v := range vars {
+		names[i] = v.Name()
+		dtype, err := v.Attr("dtype")
+		if err != nil {
+			return nil, err
+		}
+		dtypes[i] = dtype.(DataType)
+		values[i] = v.Output(0)
+		if v.Type() == "VarHandleOp" {
+			read, err := graph.AddOperation(OpSpec{
+				Type:  "ReadVariableOp",
+				Name:  fmt.Sprintf("%s/Read_%d", prefix, i),
+				Input: []Input{v.Output(0)},
+				Attrs: map[string]interface{}{"dtype": dtypes[i]},
+			})
+			if err != nil {
+				return nil, err
+			}
+			values[i] = read.Output(0)
+		}
+	}
+
+	addConst := func(name string, value interface{}) (Output, error) {
+		t, err := NewTensor(value)
+		if err != nil {
+			return Output{}, err
+		}
+		op, err := graph.AddOperation(OpSpec{
+			Type:  "Const",
+			Name:  prefix + "/" + name,
+			Attrs: map[string]interface{}{"dtype": t.DataType(), "value": t},
+		})
+		if err != nil {
+			return Output{}, err
+		}
+		return op.Output(0), nil
+	}
+	filename, err := addConst("Const", "model")
+	if err != nil {
+		return nil, err
+	}
+	namesConst, err := addConst("tensor_names", names)
+	if err != nil {
+		return nil, err
+	}
+	slicesConst, err := addConst("shape_and_slices", make([]string, len(vars)))
+	if err != nil {
+		return nil, err
+	}
+	saveOp, err := graph.AddOperation(OpSpec{
+		Type:  "SaveV2",
+		Name:  prefix + "/SaveV2",
+		Input: []Input{filename, namesConst, slicesConst, values},
+	})
+	if err != nil {
+		return nil, err
+	}
+	saveTensor, err := graph.AddOperation(OpSpec{
+		Type:                "Identity",
+		Name:                prefix + "/control_dependency",
+		Input:               []Input{filename},
+		ControlDependencies: []*Operation{saveOp},
+	})
+	if err != nil {
+		return nil, err
+	}
+	restoreOp, err := graph.AddOperation(OpSpec{
+		Type:  "RestoreV2",
+		Name:  prefix + "/RestoreV2",
+		Input: []Input{filename, namesConst, slicesConst},
+		Attrs: map[string]interface{}{"dtypes": dtypes},
+	})
+	if err != nil {
+		return nil, err
+	}
+	assignOps := make([]*Operation, len(vars))
+	for i, v := range vars {
+		assignType := "Assign"
+		if v.Type() == "VarHandleOp" {
+			assignType = "AssignVariableOp"
+		}
+		if assignOps[i], err = graph.AddOperation(OpSpec{
+			Type:  assignType,
+			Name:  fmt.Sprintf("%s/Assign_%d", prefix, i),
+			Input: []Input{v.Output(0), restoreOp.Output(i)},
+		}); err != nil {
+			return nil, err
+		}
+	}
+	restoreAll, err := graph.AddOperation(OpSpec{
+		Type:                "NoOp",
+		Name:                prefix + "/restore_all",
+		ControlDependencies: assignOps,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	variablesDir := filepath.Join(exportDir, "variables")
+	if err := os.MkdirAll(variablesDir, 0o755); err != nil {
+		return nil, err
+	}
+	path, err := NewTensor(filepath.Join(variablesDir, "variables"))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := session.Run(map[Output]*Tensor{filename: path}, nil, []*Operation{saveOp}); err != nil {
+		return nil, fmt.Errorf("failed to save variables: %w", err)
+	}
+	return &pbs.SaverDef{
+		FilenameTensorName:        filename.Op.Name() + ":0",
+		SaveTensorName:            saveTensor.Name() + ":0",
+		RestoreOpName:             restoreAll.Name(),
+		MaxToKeep:                 5,
+		KeepCheckpointEveryNHours: 10000,
+		Version:                   pbs.SaverDef_V2,
+	}, nil
+}
+
+// saveAssets copies the asset files to the "assets" subdirectory and
+// describes the tensors which are fed with their paths.
+func saveAssets(exportDir string, graph *Graph, assets map[string]string) ([]*pbs.AssetFileDef, error) {
+	tensorNames := make([]string, 0, len(assets))
+	for name := range assets {
+		tensorNames = append(tensorNames, name)
+	}
+	sort.Strings(tensorNames)
+	files := make(map[string]string) // the asset files by their file names
+	defs := make([]*pbs.AssetFileDef, len(tensorNames))
+	for i, tensorName := range tensorNames {
+		output, err := graph.outputByName(tensorName)
+		if err != nil {
+			return nil, fmt.Errorf("invalid asset tensor: %w", err)
+		}
+		if dtype := output.DataType(); dtype != String {
+			return nil, fmt.Errorf("asset tensor %q has type %v instead of String", tensorName, dtype)
+		}
+		file := assets[tensorName]
+		name := filepath.Base(file)
+		if other, ok := files[name]; ok && other != file {
+			return nil, fmt.Errorf("assets %q and %q have the same file name", other, file)
+		}
+		files[name] = file
+		defs[i] = &pbs.AssetFileDef{
+			TensorInfo: tensorInfoToProto(TensorInfo{
+				Name:  fmt.Sprintf("%s:%d", output.Op.Name(), output.Index),
+				DType: String,
+				Shape: ScalarShape(),
+			}),
+			Filename: name,
+		}
+	}
+
+	assetsDir := filepath.Join(exportDir, "assets")
+	if err := os.MkdirAll(assetsDir, 0o755); err != nil {
+		return nil, err
+	}
+	for name, file := range files {
+		if err := copyFile(filepath.Join(assetsDir, name), file); err != nil {
+			return nil, err
+		}
+	}
+	return defs, nil
+}
+
+func copyFile(dst, src string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
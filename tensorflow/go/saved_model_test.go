@@ -19,9 +19,13 @@ package tensorflow
 import (
 	"fmt"
 	"math"
+	"os"
+	"reflect"
 	"sort"
 	"strings"
 	"testing"
+
+	"github.com/hdu-hh/tensorflow/tensorflow/go/pbs"
 )
 
 const savedModelSample = "testdata/saved_model/half_plus_two/00000123"
@@ -93,6 +97,37 @@ func TestSavedModelHalfPlusTwo(t *testing.T) {
 	// Add a more thorough test when the generated protobufs are available.
 }
 
+func TestLoadSavedModelWithOptions(t *testing.T) {
+	runOptions := &pbs.RunOptions{TraceLevel: pbs.RunOptions_FULL_TRACE}
+	m, err := LoadSavedModelWithOptions(savedModelSample, []string{"serve"}, nil, runOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m.MetaGraphDef.GetMetaInfoDef().GetTags(); !reflect.DeepEqual(got, []string{"serve"}) {
+		t.Errorf("Got tags %v", got)
+	}
+	if m.SaverDef.GetRestoreOpName() == "" {
+		t.Errorf("SaverDef has no restore operation: %v", m.SaverDef)
+	}
+	if _, ok := m.CollectionDefs["variables"]; !ok {
+		t.Errorf("No variables collection in %v", m.CollectionDefs)
+	}
+	if m.ObjectGraph != nil {
+		t.Errorf("Got an object graph for a TensorFlow 1 model")
+	}
+	if len(m.Assets) != 1 {
+		t.Fatalf("Got assets %v, want 1", m.Assets)
+	}
+	for name, path := range m.Assets {
+		if m.Graph.Operation(strings.TrimSuffix(name, ":0")) == nil {
+			t.Errorf("Asset tensor %q not found in graph", name)
+		}
+		if _, err := os.Stat(path); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
 func TestSavedModelWithEmptyTags(t *testing.T) {
 	var (
 		exportDir = savedModelSample
@@ -137,3 +172,286 @@ func TestListSavedModelDetails(t *testing.T) {
 		t.Errorf("wrong signature names:\n\tgot %q\n\twant %q", got, want)
 	}
 }
+
+func TestSaveSavedModel(t *testing.T) {
+	g := NewGraph()
+	x := _Placeholder(g, "x", Float)
+	w, err := g.AddOperation(OpSpec{
+		Type:  "VariableV2",
+		Name:  "w",
+		Attrs: map[string]interface{}{"dtype": Float, "shape": MakeShape(2)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	initW, err := g.AddOperation(OpSpec{
+		Type:  "Assign",
+		Name:  "init_w",
+		Input: []Input{w.Output(0), _Const(g, "w_value", []float32{3, 4})},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := _VarHandle(g, "b", Float, MakeShape(2))
+	initB, err := g.AddOperation(OpSpec{
+		Type:  "AssignVariableOp",
+		Name:  "init_b",
+		Input: []Input{b, _Const(g, "b_value", []float32{-1, 1})},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	readB, err := g.AddOperation(OpSpec{
+		Type:  "ReadVariableOp",
+		Name:  "read_b",
+		Input: []Input{b},
+		Attrs: map[string]interface{}{"dtype": Float},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mul, err := g.AddOperation(OpSpec{Type: "Mul", Name: "mul", Input: []Input{x, w.Output(0)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_Add(g, "y", mul.Output(0), readB.Output(0))
+	// the vocabulary variable is initialized from the asset
+	vocabPath := _Const(g, "vocab_path", "vocab.txt")
+	vocab, err := g.AddOperation(OpSpec{
+		Type:  "VariableV2",
+		Name:  "vocab",
+		Attrs: map[string]interface{}{"dtype": String, "shape": ScalarShape()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	readVocab, err := g.AddOperation(OpSpec{Type: "ReadFile", Name: "read_vocab", Input: []Input{vocabPath}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	initVocab, err := g.AddOperation(OpSpec{
+		Type:  "Assign",
+		Name:  "init_vocab",
+		Input: []Input{vocab.Output(0), readVocab.Output(0)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sess, err := NewSession(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	asset := dir + "/vocab.txt"
+	if err := os.WriteFile(asset, []byte("a\nb\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	feeds := map[Output]*Tensor{vocabPath: mustTensor(t, asset)}
+	if _, err := sess.Run(feeds, nil, []*Operation{initW, initB, initVocab}); err != nil {
+		t.Fatal(err)
+	}
+	exportDir := dir + "/model"
+	signatures := map[string]Signature{
+		"serving_default": {
+			Inputs:     map[string]TensorInfo{"x": {Name: "x:0", DType: Float, Shape: MakeShape(-1)}},
+			Outputs:    map[string]TensorInfo{"y": {Name: "y:0", DType: Float, Shape: MakeShape(-1)}},
+			MethodName: "tensorflow/serving/predict",
+		},
+		"__saved_model_init_op": {
+			Outputs: map[string]TensorInfo{"__saved_model_init_op": {Name: "init_vocab"}},
+		},
+	}
+	assets := map[string]string{"vocab_path": asset}
+	if err := SaveSavedModel(exportDir, g, sess, []string{"serve"}, signatures, assets); err != nil {
+		t.Fatal(err)
+	}
+	// the loaded model must read the copy of the asset
+	if err := os.WriteFile(asset, []byte("changed"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveSavedModel(exportDir, g, sess, []string{"serve"}, signatures, nil); err == nil {
+		t.Errorf("Overwriting a SavedModel did not fail")
+	}
+	for _, name := range []string{"saved_model.pb", "variables/variables.index", "variables/variables.data-00000-of-00001", "assets/vocab.txt"} {
+		if _, err := os.Stat(exportDir + "/" + name); err != nil {
+			t.Error(err)
+		}
+	}
+
+	m, err := LoadSavedModel(exportDir, []string{"serve"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Signatures["serving_default"].MethodName; got != "tensorflow/serving/predict" {
+		t.Errorf("Got method name %q", got)
+	}
+	if got, want := m.Assets["vocab_path:0"], exportDir+"/assets/vocab.txt"; got != want {
+		t.Errorf("Got asset %q, want %q", got, want)
+	}
+	if m.SaverDef.GetVersion() != pbs.SaverDef_V2 {
+		t.Errorf("Got SaverDef %v", m.SaverDef)
+	}
+	input, err := NewTensor([]float32{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := m.Session.Run(
+		map[Output]*Tensor{m.Graph.Operation("x").Output(0): input},
+		[]Output{m.Graph.Operation("y").Output(0)},
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := output[0].Value(), []float32{2, 9}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %v, want %v", got, want)
+	}
+	output, err = m.Session.Run(nil, []Output{m.Graph.Operation("vocab").Output(0)}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := output[0].Value(), "a\nb\n"; got != want {
+		t.Errorf("Got vocabulary %q, want %q", got, want)
+	}
+
+	// assets with the same file name would overwrite each other
+	if err := os.Mkdir(dir+"/other", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dir+"/other/vocab.txt", nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	assets["read_vocab:0"] = dir + "/other/vocab.txt"
+	if err := SaveSavedModel(dir+"/model2", g, sess, []string{"serve"}, nil, assets); err == nil {
+		t.Errorf("Assets with the same file name did not fail")
+	}
+}
+
+func TestSavedModelSignature(t *testing.T) {
+	g := NewGraph()
+	_Neg(g, "y", _Placeholder(g, "x", Float))
+	_Placeholder(g, "indices", Int64)
+	_Placeholder(g, "dense_shape", Int64)
+	_Neg(g, "neg_values", _Placeholder(g, "values", Float))
+	sess, err := NewSession(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exportDir := t.TempDir() + "/model"
+	signatures := map[string]Signature{"serving_default": {
+		Inputs: map[string]TensorInfo{
+			"x": {Name: "x:0", DType: Float, Shape: MakeShape(-1, 2)},
+			"sp": {DType: Float, Shape: MakeShape(-1, 3), CooSparse: &CooSparseInfo{
+				ValuesName:     "values:0",
+				IndicesName:    "indices:0",
+				DenseShapeName: "dense_shape:0",
+			}},
+		},
+		Outputs: map[string]TensorInfo{
+			"y": {Name: "y:0", DType: Float, Shape: MakeShape(-1, 2)},
+			"neg_sp": {DType: Float, Shape: MakeShape(-1, 3), CooSparse: &CooSparseInfo{
+				ValuesName:     "neg_values:0",
+				IndicesName:    "indices:0",
+				DenseShapeName: "dense_shape:0",
+			}},
+		},
+		MethodName: "tensorflow/serving/predict",
+	}}
+	if err := SaveSavedModel(exportDir, g, sess, []string{"serve"}, signatures, nil); err != nil {
+		t.Fatal(err)
+	}
+	m, err := LoadSavedModel(exportDir, []string{"serve"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Signatures["serving_default"].Inputs["sp"].CooSparse; got == nil || got.ValuesName != "values:0" {
+		t.Errorf("Got sparse input %v", got)
+	}
+
+	runner := m.Signature("serving_default")
+	if m.Signature("serving_default") != runner {
+		t.Errorf("Runner was not cached")
+	}
+	inputs := map[string]*Tensor{
+		"x":              mustTensor(t, [][]float32{{1, 2}}),
+		"sp/indices":     mustTensor(t, [][]int64{{0, 1}, {1, 2}}),
+		"sp/values":      mustTensor(t, []float32{3, 4}),
+		"sp/dense_shape": mustTensor(t, []int64{2, 3}),
+	}
+	outputs, err := runner.Run(inputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"y":                  [][]float32{{-1, -2}},
+		"neg_sp/indices":     [][]int64{{0, 1}, {1, 2}},
+		"neg_sp/values":      []float32{-3, -4},
+		"neg_sp/dense_shape": []int64{2, 3},
+	}
+	if len(outputs) != len(want) {
+		t.Errorf("Got %d outputs, want %d", len(outputs), len(want))
+	}
+	for key, value := range want {
+		if got := outputs[key]; got == nil || !reflect.DeepEqual(got.Value(), value) {
+			t.Errorf("Got output %q = %v, want %v", key, got, value)
+		}
+	}
+
+	invalid := []struct {
+		name  string
+		key   string
+		value *Tensor
+	}{
+		{"wrong data type", "x", mustTensor(t, [][]int32{{1, 2}})},
+		{"wrong shape", "x", mustTensor(t, [][]float32{{1, 2, 3}})},
+		{"wrong rank", "x", mustTensor(t, []float32{1, 2})},
+		{"unknown key", "z", mustTensor(t, float32(1))},
+		{"missing key", "x", nil},
+	}
+	for _, test := range invalid {
+		feeds := make(map[string]*Tensor)
+		for key, value := range inputs {
+			feeds[key] = value
+		}
+		if test.value != nil {
+			feeds[test.key] = test.value
+		} else {
+			delete(feeds, test.key)
+		}
+		if _, err := runner.Run(feeds); err == nil {
+			t.Errorf("Run with %s did not fail", test.name)
+		}
+	}
+	if _, err := m.Signature("missing").Run(inputs); err == nil {
+		t.Errorf("Run of a missing signature did not fail")
+	}
+}
+
+func TestSavedModelSignatureUnknownRank(t *testing.T) {
+	m, err := LoadSavedModel(savedModelSample, []string{"serve"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := m.Signatures["regress_x_to_y"].Inputs["inputs"]
+	if rank := input.Shape.NumDimensions(); rank != -1 {
+		t.Errorf("Got rank %d of the serialized examples, want an unknown rank", rank)
+	}
+	example := func(x float32) string {
+		value := func(v float32) *pbs.Feature {
+			return &pbs.Feature{Kind: &pbs.Feature_FloatList{FloatList: &pbs.FloatList{Value: []float32{v}}}}
+		}
+		return string(pbs.MustMarshal(&pbs.Example{Features: &pbs.Features{
+			Feature: map[string]*pbs.Feature{"x": value(x), "x2": value(0)},
+		}}))
+	}
+	outputs, err := m.Signature("regress_x_to_y").Run(map[string]*Tensor{
+		"inputs": mustTensor(t, []string{example(1), example(3)}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := outputs["outputs"].Value(), [][]float32{{2.5}, {3.5}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tensorflow
+
+// #include "tensorflow/c/c_api.h"
+import "C"
+
+import (
+	"fmt"
+	"runtime"
+	"unsafe"
+
+	"github.com/hdu-hh/tensorflow/tensorflow/go/pbs"
+	"google.golang.org/protobuf/proto"
+)
+
+// Server is an in-process TensorFlow server which serves a task of a
+// cluster, e.g. a remote worker for a [Context] or a [Session].
+type Server struct {
+	c *C.TF_Server
+}
+
+// NewServer creates a server for the task specified by the
+// [pbs.ServerDef]. It does not serve requests until it is started.
+func NewServer(def *pbs.ServerDef) (*Server, error) {
+	buf, err := proto.Marshal(def)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ServerDef: %w", err)
+	}
+	if len(buf) == 0 {
+		buf = []byte{0}
+	}
+	status := newStatus()
+	cServer := C.TF_NewServer(unsafe.Pointer(&buf[0]), C.size_t(len(buf)), status.c)
+	if err := status.Err(); err != nil {
+		return nil, err
+	}
+	s := &Server{c: cServer}
+	runtime.SetFinalizer(s, (*Server).finalizer)
+	return s, nil
+}
+
+func (s *Server) finalizer() {
+	C.TF_DeleteServer(s.c)
+}
+
+// Start starts serving requests.
+func (s *Server) Start() error {
+	status := newStatus()
+	C.TF_ServerStart(s.c, status.c)
+	runtime.KeepAlive(s)
+	return status.Err()
+}
+
+// Stop stops serving requests.
+func (s *Server) Stop() error {
+	status := newStatus()
+	C.TF_ServerStop(s.c, status.c)
+	runtime.KeepAlive(s)
+	return status.Err()
+}
+
+// Join blocks until the server has been stopped.
+func (s *Server) Join() error {
+	status := newStatus()
+	C.TF_ServerJoin(s.c, status.c)
+	runtime.KeepAlive(s)
+	return status.Err()
+}
+
+// Target returns the target to connect a [Session] to the server,
+// see [SessionOptions].
+func (s *Server) Target() string {
+	defer runtime.KeepAlive(s)
+	return C.GoString(C.TF_ServerTarget(s.c))
+}
@@ -21,12 +21,17 @@ package tensorflow
 import "C"
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"runtime"
 	"sort"
 	"sync"
+	"time"
 	"unsafe"
+
+	"github.com/hdu-hh/tensorflow/tensorflow/go/pbs"
+	"google.golang.org/protobuf/proto"
 )
 
 // Session drives a TensorFlow graph computation.
@@ -43,8 +48,11 @@ type Session struct {
 	// For ensuring that:
 	// - Close() blocks on all Run() calls to complete.
 	// - Close() can be called multiple times.
-	wg sync.WaitGroup
-	mu sync.Mutex
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	closing bool // no new steps are started
+
+	cancelTargets []*Operation // run to abort the steps of canceled contexts
 }
 
 // NewSession creates a new execution session with the associated graph.
@@ -135,30 +143,211 @@ type FeedMap map[Output]*Tensor
 // the fetches argument. If fetches is set to nil, the returned Tensor fetches
 // is empty.
 func (s *Session) Run(feeds FeedMap, fetches []Output, targets []*Operation) ([]*Tensor, error) {
+	c := newCRunArgs(feeds, fetches, targets)
+	err := s.run(c, nil, nil)
+
+	// Make sure GC won't harvest input tensors until SessionRun() is finished
+	runtime.KeepAlive(feeds)
+
+	if err != nil {
+		return nil, err
+	}
+	return c.toGo(), nil
+}
+
+// RunResult contains the results of a [Session.RunWithOptions] call.
+type RunResult struct {
+	// Fetched contains the fetched Tensors in the same order
+	// as supplied in the fetches argument.
+	Fetched []*Tensor
+
+	// Metadata contains the information collected during the run,
+	// e.g. the step stats, the cost graph or the partition graphs
+	// when they were requested by the run options.
+	Metadata *pbs.RunMetadata
+}
+
+// RunWithOptions runs the graph like [Session.Run] but also allows to
+// specify the [pbs.RunOptions] for this step, e.g. its tracing level or
+// timeout. The options may be nil to use the default options.
+//
+// The returned [RunResult] contains the fetched Tensors and the [pbs.RunMetadata]
+// of the run, e.g. the [pbs.StepStats] with the per-operation timings
+// when the options requested a trace.
+func (s *Session) RunWithOptions(feeds FeedMap, fetches []Output, targets []*Operation, options *pbs.RunOptions) (*RunResult, error) {
+	var cOptions *C.TF_Buffer
+	if options != nil {
+		buf, err := proto.Marshal(options)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RunOptions: %w", err)
+		}
+		cOptions = newBufferFromSlice(buf)
+		defer C.TF_DeleteBuffer(cOptions)
+	}
+	cMetadata := C.TF_NewBuffer()
+	defer C.TF_DeleteBuffer(cMetadata)
+
+	c := newCRunArgs(feeds, fetches, targets)
+	err := s.run(c, cOptions, cMetadata)
+	runtime.KeepAlive(feeds)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := new(pbs.RunMetadata)
+	if cMetadata.length > 0 {
+		buf, err := getBufferAsSlice(cMetadata)
+		if err != nil {
+			return nil, err
+		}
+		if err := proto.Unmarshal(buf, metadata); err != nil {
+			return nil, fmt.Errorf("invalid RunMetadata: %w", err)
+		}
+	}
+	return &RunResult{Fetched: c.toGo(), Metadata: metadata}, nil
+}
+
+// RunContext runs the graph like [Session.Run] but aborts the step when
+// ctx is done.
+//
+// A deadline of ctx is mapped to the timeout of the step, so that TensorFlow
+// aborts only this step when the deadline is exceeded. TensorFlow cannot
+// cancel a single step otherwise, so when ctx is canceled, RunContext runs
+// the cancel targets of the session (see [Session.SetCancelTargets]). They
+// abort all steps using e.g. the closed queues, not just this one, and the
+// queues remain closed for the following steps. Without cancel targets,
+// RunContext waits for the step until the deadline of ctx, and it fails
+// without starting the step if ctx can be canceled but has no deadline.
+// RunContext always returns after the step has stopped.
+//
+// If ctx is done, the returned error wraps ctx.Err() and, if the step
+// failed, also the [Error] reported by TensorFlow.
+func (s *Session) RunContext(ctx context.Context, feeds FeedMap, fetches []Output, targets []*Operation) ([]*Tensor, error) {
+	var options *pbs.RunOptions
+	deadline, hasDeadline := ctx.Deadline()
+	if hasDeadline {
+		options = &pbs.RunOptions{TimeoutInMs: timeoutInMs(deadline)}
+	}
+	var res *RunResult
+	err := s.runContext(ctx, hasDeadline, func() (err error) {
+		res, err = s.RunWithOptions(feeds, fetches, targets, options)
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.Fetched, nil
+}
+
+// timeoutInMs returns the milliseconds remaining until the deadline.
+// It is never less than one as a zero timeout disables timeouts.
+func timeoutInMs(deadline time.Time) int64 {
+	if ms := time.Until(deadline).Milliseconds(); ms > 0 {
+		return ms
+	}
+	return 1
+}
+
+// SetCancelTargets sets the operations which are run to abort the steps of
+// [Session.RunContext] and [PartialRun.RunContext] when their contexts are
+// canceled, typically QueueCloseV2 operations with cancel_pending_enqueues
+// set, which release the steps blocked on the queues. Note that running them
+// affects all concurrent steps of the session and that the queues remain
+// closed for the following steps.
+func (s *Session) SetCancelTargets(targets ...*Operation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cancelTargets = append([]*Operation(nil), targets...)
+}
+
+// runContext executes the step in the background and waits until it is
+// finished or until ctx is done. If the step has a timeout for the deadline
+// of ctx, TensorFlow aborts it itself when the deadline is exceeded.
+// Otherwise it is aborted by running the cancel targets. Steps which could
+// not be aborted are not started, so that they are never left running.
+func (s *Session) runContext(ctx context.Context, hasTimeout bool, step func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	targets := s.cancelTargets
+	s.mu.Unlock()
+	if len(targets) == 0 && ctx.Done() != nil && !hasTimeout {
+		return errors.New("the step of a cancelable context without deadline requires cancel targets")
+	}
+	done := make(chan error, 1)
+	go func() { done <- step() }()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		if len(targets) == 0 || hasTimeout && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = <-done // TensorFlow aborts the step at its timeout
+			break
+		}
+		if abortErr := s.abort(targets); abortErr != nil {
+			return &contextError{ctxErr: ctx.Err(), err: fmt.Errorf("failed to abort step: %w", abortErr)}
+		}
+		if err = <-done; err == nil {
+			return ctx.Err()
+		}
+	}
+	if err != nil && ctx.Err() != nil {
+		return &contextError{ctxErr: ctx.Err(), err: err}
+	}
+	return err
+}
+
+// abort runs the targets to abort a running step. Unlike other steps, it
+// also runs while the session is closing, since Close waits for the step.
+func (s *Session) abort(targets []*Operation) error {
 	s.mu.Lock()
 	if s.c == nil {
 		s.mu.Unlock()
-		return nil, errors.New("session is closed")
+		return errors.New("session is closed")
 	}
 	s.wg.Add(1)
 	s.mu.Unlock()
 	defer s.wg.Done()
 
-	c := newCRunArgs(feeds, fetches, targets)
+	c := newCRunArgs(nil, nil, targets)
 	status := newStatus()
-	C.TF_SessionRun(s.c, nil,
-		ptrOutput(c.feeds), ptrTensor(c.feedTensors), C.int(len(feeds)),
-		ptrOutput(c.fetches), ptrTensor(c.fetchTensors), C.int(len(fetches)),
-		ptrOperation(c.targets), C.int(len(targets)),
-		nil, status.c)
+	C.TF_SessionRun(s.c, nil, nil, nil, 0, nil, nil, 0,
+		ptrOperation(c.targets), C.int(len(c.targets)), nil, status.c)
+	return status.Err()
+}
 
-	// Make sure GC won't harvest input tensors until SessionRun() is finished
-	runtime.KeepAlive(feeds)
+// contextError reports a step that failed after its context was done.
+// It unwraps to the context error but also matches the step error,
+// so that e.g. its TensorFlow [Error] can still be retrieved.
+type contextError struct {
+	ctxErr, err error
+}
 
-	if err := status.Err(); err != nil {
-		return nil, err
+func (e *contextError) Error() string              { return e.ctxErr.Error() + ": " + e.err.Error() }
+func (e *contextError) Unwrap() error              { return e.ctxErr }
+func (e *contextError) Is(target error) bool       { return errors.Is(e.err, target) }
+func (e *contextError) As(target interface{}) bool { return errors.As(e.err, target) }
+
+// run executes a step with the prepared C arguments and
+// the optional serialized run options and run metadata buffers.
+func (s *Session) run(c *cRunArgs, cOptions, cMetadata *C.TF_Buffer) error {
+	s.mu.Lock()
+	if s.c == nil || s.closing {
+		s.mu.Unlock()
+		return errors.New("session is closed")
 	}
-	return c.toGo(), nil
+	s.wg.Add(1)
+	s.mu.Unlock()
+	defer s.wg.Done()
+
+	status := newStatus()
+	C.TF_SessionRun(s.c, cOptions,
+		ptrOutput(c.feeds), ptrTensor(c.feedTensors), C.int(len(c.feeds)),
+		ptrOutput(c.fetches), ptrTensor(c.fetchTensors), C.int(len(c.fetches)),
+		ptrOperation(c.targets), C.int(len(c.targets)),
+		cMetadata, status.c)
+	return status.Err()
 }
 
 // PartialRun enables incremental evaluation of graphs.
@@ -190,7 +379,7 @@ func (pr *PartialRun) Run(feeds FeedMap, fetches []Output, targets []*Operation)
 		s      = pr.session
 	)
 	s.mu.Lock()
-	if s.c == nil {
+	if s.c == nil || s.closing {
 		s.mu.Unlock()
 		return nil, errors.New("session is closed")
 	}
@@ -209,6 +398,30 @@ func (pr *PartialRun) Run(feeds FeedMap, fetches []Output, targets []*Operation)
 	return c.toGo(), nil
 }
 
+// RunContext resumes execution of the graph like [PartialRun.Run]
+// but aborts the step when ctx is done.
+//
+// Partial runs do not support timeouts, so the step is aborted by running
+// the cancel targets of the session (see [Session.SetCancelTargets]) both
+// when ctx is canceled and when its deadline is exceeded, with the same
+// effects on other steps as for [Session.RunContext]. Without cancel targets,
+// RunContext fails without resuming the step unless ctx can never be done.
+// The partial run should not be used any further after an aborted step.
+//
+// If ctx is done, the returned error wraps ctx.Err() and, if the step
+// failed, also the [Error] reported by TensorFlow.
+func (pr *PartialRun) RunContext(ctx context.Context, feeds FeedMap, fetches []Output, targets []*Operation) ([]*Tensor, error) {
+	var fetched []*Tensor
+	err := pr.session.runContext(ctx, false, func() (err error) {
+		fetched, err = pr.Run(feeds, fetches, targets)
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fetched, nil
+}
+
 // NewPartialRun sets up the graph for incremental evaluation.
 //
 // All values of feeds, fetches and targets that may be provided to Run calls
@@ -246,7 +459,7 @@ func (s *Session) NewPartialRun(feeds, fetches []Output, targets []*Operation) (
 	}
 
 	s.mu.Lock()
-	if s.c == nil {
+	if s.c == nil || s.closing {
 		s.mu.Unlock()
 		return nil, errors.New("session is closed")
 	}
@@ -272,9 +485,13 @@ func (s *Session) NewPartialRun(feeds, fetches []Output, targets []*Operation) (
 // Close a session. This contacts any other processes associated with this
 // session, if applicable. Blocks until all previous calls to Run have returned.
 func (s *Session) Close() error {
+	// wait without holding the lock, so that canceled steps can be aborted
 	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.closing = true
+	s.mu.Unlock()
 	s.wg.Wait()
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.c == nil {
 		return nil
 	}
@@ -17,9 +17,12 @@ limitations under the License.
 package tensorflow
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/hdu-hh/tensorflow/tensorflow/go/pbs"
 )
@@ -369,6 +372,162 @@ func TestSessionConfig(t *testing.T) {
 	}
 }
 
+func TestSessionRunWithOptions(t *testing.T) {
+	tensor, err := NewTensor([]float32{1, -2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	graph, inp, out := createTestGraph(t, tensor.DataType())
+	s, err := NewSession(graph, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	opts := &pbs.RunOptions{TraceLevel: pbs.RunOptions_FULL_TRACE}
+	res, err := s.RunWithOptions(FeedMap{inp: tensor}, []Output{out}, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := res.Fetched[0].Value(), []float32{-1, 2, -3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %v, want %v", got, want)
+	}
+	var numNodeStats int
+	for _, dev := range res.Metadata.GetStepStats().GetDevStats() {
+		numNodeStats += len(dev.GetNodeStats())
+	}
+	if numNodeStats == 0 {
+		t.Errorf("no node stats in traced run metadata: %v", res.Metadata)
+	}
+
+	// nil options behave like Session.Run
+	res, err = s.RunWithOptions(FeedMap{inp: tensor}, []Output{out}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Fetched) != 1 {
+		t.Fatalf("Got %d fetched tensors, want 1", len(res.Fetched))
+	}
+}
+
+// createBlockingGraph returns a graph with a dequeue operation
+// blocking on an empty queue until the queue gets closed.
+func createBlockingGraph(t *testing.T) (g *Graph, dequeue Output, closeQueue *Operation) {
+	g = NewGraph()
+	queue, err := g.AddOperation(OpSpec{
+		Type:  "FIFOQueueV2",
+		Attrs: map[string]interface{}{"component_types": []DataType{Float}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deq, err := g.AddOperation(OpSpec{
+		Type:  "QueueDequeueV2",
+		Input: []Input{queue.Output(0)},
+		Attrs: map[string]interface{}{"component_types": []DataType{Float}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	closeQueue, err = g.AddOperation(OpSpec{
+		Type:  "QueueCloseV2",
+		Input: []Input{queue.Output(0)},
+		Attrs: map[string]interface{}{"cancel_pending_enqueues": true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g, deq.Output(0), closeQueue
+}
+
+func TestSessionRunContextDeadline(t *testing.T) {
+	g, dequeue, _ := createBlockingGraph(t)
+	s, err := NewSession(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := s.RunContext(ctx, nil, []Output{dequeue}, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Got error %v, want %v", err, context.DeadlineExceeded)
+	}
+	// the step has been aborted by its timeout so closing must not block
+	if err := s.Close(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSessionRunContextCancel(t *testing.T) {
+	g, dequeue, closeQueue := createBlockingGraph(t)
+	s, err := NewSession(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetCancelTargets(closeQueue)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	_, err = s.RunContext(ctx, nil, []Output{dequeue}, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Got error %v, want %v", err, context.Canceled)
+	}
+	// the step has been aborted by closing its queue
+	var tfErr *Error
+	if !errors.As(err, &tfErr) || tfErr.Code != OutOfRange {
+		t.Errorf("Got error %v, want the TensorFlow error of the closed queue", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Error(err)
+	}
+	// a context that is already done does not start a step
+	if _, err := s.RunContext(ctx, nil, []Output{dequeue}, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Got error %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestSessionRunContextWithoutCancelTargets(t *testing.T) {
+	g, dequeue, _ := createBlockingGraph(t)
+	s, err := NewSession(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// the step could not be aborted, so it must not be started
+	if _, err := s.RunContext(ctx, nil, []Output{dequeue}, nil); err == nil || errors.Is(err, context.Canceled) {
+		t.Errorf("Got error %v, want an error about the missing cancel targets", err)
+	}
+	// a canceled step with a deadline stops at its timeout
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if _, err := s.RunContext(ctx, nil, []Output{dequeue}, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Got error %v, want %v", err, context.Canceled)
+	}
+	// no step is left running, so closing must not block
+	if err := s.Close(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPartialRunContextCancel(t *testing.T) {
+	g, dequeue, closeQueue := createBlockingGraph(t)
+	s, err := NewSession(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	s.SetCancelTargets(closeQueue)
+	pr, err := s.NewPartialRun(nil, []Output{dequeue}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := pr.RunContext(ctx, nil, []Output{dequeue}, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Got error %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
 func TestListDevices(t *testing.T) {
 	s, err := NewSession(NewGraph(), nil)
 	if err != nil {
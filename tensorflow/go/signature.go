@@ -89,6 +89,23 @@ type TensorInfo struct {
 	Name  string
 	DType DataType
 	Shape Shape
+
+	// CooSparse is set instead of Name for sparse tensors, which consist of
+	// a values, an indices and a dense shape tensor. DType and Shape are
+	// the ones of the dense tensor.
+	CooSparse *CooSparseInfo
+
+	// Components are set instead of Name for composite tensors, like ragged
+	// tensors, which consist of component tensors. TypeSpec describes how
+	// they are composed.
+	Components []TensorInfo
+	TypeSpec   *corepb.TypeSpecProto
+}
+
+// CooSparseInfo contains the names of the tensors of a sparse tensor in the
+// coordinate list (COO) format.
+type CooSparseInfo struct {
+	ValuesName, IndicesName, DenseShapeName string
 }
 
 func signatureDefFromProto(pb *corepb.SignatureDef) Signature {
@@ -108,13 +125,73 @@ func signatureDefFromProto(pb *corepb.SignatureDef) Signature {
 }
 
 func tensorInfoFromProto(pb *corepb.TensorInfo) TensorInfo {
-	var dims []int64
-	for _, d := range pb.GetTensorShape().GetDim() {
-		dims = append(dims, d.GetSize())
-	}
-	return TensorInfo{
+	info := TensorInfo{
 		Name:  pb.GetName(),
 		DType: DataType(C.TF_DataType(pb.GetDtype())),
-		Shape: MakeShape(dims...),
 	}
+	if shape := pb.GetTensorShape(); shape != nil { // a missing shape is unknown
+		info.Shape = shapeFromProto(shape)
+	}
+	if sparse := pb.GetCooSparse(); sparse != nil {
+		info.CooSparse = &CooSparseInfo{
+			ValuesName:     sparse.GetValuesTensorName(),
+			IndicesName:    sparse.GetIndicesTensorName(),
+			DenseShapeName: sparse.GetDenseShapeTensorName(),
+		}
+	}
+	if composite := pb.GetCompositeTensor(); composite != nil {
+		info.TypeSpec = composite.GetTypeSpec()
+		info.Components = make([]TensorInfo, len(composite.GetComponents()))
+		for i, component := range composite.GetComponents() {
+			info.Components[i] = tensorInfoFromProto(component)
+		}
+	}
+	return info
+}
+
+func signatureDefToProto(signature Signature) *corepb.SignatureDef {
+	pb := &corepb.SignatureDef{
+		Inputs:     make(map[string]*corepb.TensorInfo),
+		Outputs:    make(map[string]*corepb.TensorInfo),
+		MethodName: signature.MethodName,
+	}
+	for name, input := range signature.Inputs {
+		pb.Inputs[name] = tensorInfoToProto(input)
+	}
+	for name, output := range signature.Outputs {
+		pb.Outputs[name] = tensorInfoToProto(output)
+	}
+	return pb
+}
+
+func tensorInfoToProto(info TensorInfo) *corepb.TensorInfo {
+	pb := &corepb.TensorInfo{
+		Encoding:    &corepb.TensorInfo_Name{Name: info.Name},
+		Dtype:       corepb.DataType(info.DType),
+		TensorShape: tensorShapeToProto(info.Shape),
+	}
+	switch {
+	case info.CooSparse != nil:
+		pb.Encoding = &corepb.TensorInfo_CooSparse_{CooSparse: &corepb.TensorInfo_CooSparse{
+			ValuesTensorName:     info.CooSparse.ValuesName,
+			IndicesTensorName:    info.CooSparse.IndicesName,
+			DenseShapeTensorName: info.CooSparse.DenseShapeName,
+		}}
+	case info.Components != nil:
+		composite := &corepb.TensorInfo_CompositeTensor{TypeSpec: info.TypeSpec}
+		for _, component := range info.Components {
+			composite.Components = append(composite.Components, tensorInfoToProto(component))
+		}
+		pb.Encoding = &corepb.TensorInfo_CompositeTensor_{CompositeTensor: composite}
+	}
+	return pb
+}
+
+// tensorShapeToProto converts a partially known Shape.
+func tensorShapeToProto(shape Shape) *corepb.TensorShapeProto {
+	dims, err := shape.ToSlice()
+	if err != nil {
+		return &corepb.TensorShapeProto{UnknownRank: true}
+	}
+	return shapeToProto(dims)
 }
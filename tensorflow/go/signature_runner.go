@@ -0,0 +1,199 @@
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tensorflow
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// SignatureRunner runs the computation described by a signature of a
+// [SavedModel]. It is created with [SavedModel.Signature].
+//
+// The inputs and outputs are keyed like in the signature. Sparse tensors
+// (with a CooSparse TensorInfo) are split into the three tensors with the
+// keys "key/indices", "key/values" and "key/dense_shape", and composite
+// tensors into their components with the keys "key/0", "key/1", and so on.
+type SignatureRunner struct {
+	model *SavedModel
+	key   string
+
+	once    sync.Once
+	err     error
+	inputs  map[string]signatureTensor
+	outputs map[string]signatureTensor
+	fetches []Output
+	keys    []string // the output keys in the order of fetches
+}
+
+// signatureTensor is a resolved tensor of a signature.
+type signatureTensor struct {
+	output Output
+	info   TensorInfo
+}
+
+// Signature returns the runner of the signature with the given key, like
+// "serving_default". The tensors of the signature are resolved on the first
+// run and cached for later runs.
+func (m *SavedModel) Signature(key string) *SignatureRunner {
+	m.runnersMu.Lock()
+	defer m.runnersMu.Unlock()
+	if r, ok := m.runners[key]; ok {
+		return r
+	}
+	if m.runners == nil {
+		m.runners = make(map[string]*SignatureRunner)
+	}
+	r := &SignatureRunner{model: m, key: key}
+	m.runners[key] = r
+	return r
+}
+
+// Run feeds the inputs into the tensors of the signature and returns all its
+// outputs. Every input of the signature must be provided. The data type and
+// shape of the inputs are validated against the signature before running
+// the session.
+func (r *SignatureRunner) Run(inputs map[string]*Tensor) (map[string]*Tensor, error) {
+	r.once.Do(r.resolve)
+	if r.err != nil {
+		return nil, r.err
+	}
+	feeds := make(map[Output]*Tensor, len(inputs))
+	for key, value := range inputs {
+		input, ok := r.inputs[key]
+		if !ok {
+			return nil, fmt.Errorf("signature %q has no input %q", r.key, key)
+		}
+		if err := input.info.check(value); err != nil {
+			return nil, fmt.Errorf("invalid input %q of signature %q: %w", key, r.key, err)
+		}
+		feeds[input.output] = value
+	}
+	if len(feeds) < len(r.inputs) {
+		var missing []string
+		for key := range r.inputs {
+			if _, ok := inputs[key]; !ok {
+				missing = append(missing, key)
+			}
+		}
+		sort.Strings(missing)
+		return nil, fmt.Errorf("missing inputs %q of signature %q", missing, r.key)
+	}
+	values, err := r.model.Session.Run(feeds, r.fetches, nil)
+	if err != nil {
+		return nil, err
+	}
+	outputs := make(map[string]*Tensor, len(values))
+	for i, value := range values {
+		outputs[r.keys[i]] = value
+	}
+	return outputs, nil
+}
+
+// resolve resolves the tensors of the signature in the graph.
+func (r *SignatureRunner) resolve() {
+	signature, ok := r.model.Signatures[r.key]
+	if !ok {
+		r.err = fmt.Errorf("signature %q not found", r.key)
+		return
+	}
+	if r.inputs, r.err = r.resolveTensors(signature.Inputs); r.err != nil {
+		return
+	}
+	if r.outputs, r.err = r.resolveTensors(signature.Outputs); r.err != nil {
+		return
+	}
+	for key := range r.outputs {
+		r.keys = append(r.keys, key)
+	}
+	sort.Strings(r.keys)
+	r.fetches = make([]Output, len(r.keys))
+	for i, key := range r.keys {
+		r.fetches[i] = r.outputs[key].output
+	}
+}
+
+// resolveTensors resolves the tensors by their flattened keys.
+func (r *SignatureRunner) resolveTensors(infos map[string]TensorInfo) (map[string]signatureTensor, error) {
+	tensors := make(map[string]signatureTensor)
+	var add func(key string, info TensorInfo) error
+	add = func(key string, info TensorInfo) error {
+		switch {
+		case info.CooSparse != nil:
+			parts := []struct {
+				suffix, name string
+				info         TensorInfo
+			}{
+				{"/indices", info.CooSparse.IndicesName, TensorInfo{DType: Int64, Shape: MakeShape(-1, int64(info.Shape.NumDimensions()))}},
+				{"/values", info.CooSparse.ValuesName, TensorInfo{DType: info.DType, Shape: MakeShape(-1)}},
+				{"/dense_shape", info.CooSparse.DenseShapeName, TensorInfo{DType: Int64, Shape: MakeShape(int64(info.Shape.NumDimensions()))}},
+			}
+			for _, part := range parts {
+				part.info.Name = part.name
+				if err := add(key+part.suffix, part.info); err != nil {
+					return err
+				}
+			}
+		case info.Components != nil:
+			for i, component := range info.Components {
+				if err := add(key+"/"+strconv.Itoa(i), component); err != nil {
+					return err
+				}
+			}
+		default:
+			output, err := r.model.Graph.outputByName(info.Name)
+			if err != nil {
+				return fmt.Errorf("failed to resolve %q of signature %q: %w", key, r.key, err)
+			}
+			tensors[key] = signatureTensor{output: output, info: info}
+		}
+		return nil
+	}
+	for key, info := range infos {
+		if err := add(key, info); err != nil {
+			return nil, err
+		}
+	}
+	return tensors, nil
+}
+
+// check checks that the data type and shape of the tensor match the
+// TensorInfo. Unknown dimensions, ranks and data types match any tensor.
+func (info TensorInfo) check(t *Tensor) error {
+	if t == nil {
+		return fmt.Errorf("nil tensor")
+	}
+	if info.DType != 0 && t.DataType() != info.DType {
+		return fmt.Errorf("got data type %v, want %v", t.DataType(), info.DType)
+	}
+	rank := info.Shape.NumDimensions()
+	if rank < 0 {
+		return nil
+	}
+	shape := t.Shape()
+	if len(shape) != rank {
+		return fmt.Errorf("got shape %v, want %v", shape, info.Shape)
+	}
+	for i, size := range shape {
+		if want := info.Shape.Size(i); want >= 0 && size != want {
+			return fmt.Errorf("got shape %v, want %v", shape, info.Shape)
+		}
+	}
+	return nil
+}
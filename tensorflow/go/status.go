@@ -19,9 +19,95 @@ package tensorflow
 // #include "tensorflow/c/c_api.h"
 import "C"
 
-import "runtime"
+import (
+	"errors"
+	"runtime"
 
-type code C.TF_Code
+	"github.com/hdu-hh/tensorflow/tensorflow/go/pbs"
+)
+
+// Code is the canonical error code of an [Error] reported by TensorFlow.
+// The codes mirror the [pbs.Code] values of
+// https://www.tensorflow.org/code/tensorflow/tsl/protobuf/error_codes.proto
+//
+// A Code also fulfills the error interface, so that e.g.
+// errors.Is(err, NotFound) reports whether err carries the NotFound code.
+type Code C.TF_Code
+
+// Canonical error codes.
+const (
+	OK                 Code = C.TF_OK
+	Cancelled          Code = C.TF_CANCELLED
+	Unknown            Code = C.TF_UNKNOWN
+	InvalidArgument    Code = C.TF_INVALID_ARGUMENT
+	DeadlineExceeded   Code = C.TF_DEADLINE_EXCEEDED
+	NotFound           Code = C.TF_NOT_FOUND
+	AlreadyExists      Code = C.TF_ALREADY_EXISTS
+	PermissionDenied   Code = C.TF_PERMISSION_DENIED
+	Unauthenticated    Code = C.TF_UNAUTHENTICATED
+	ResourceExhausted  Code = C.TF_RESOURCE_EXHAUSTED
+	FailedPrecondition Code = C.TF_FAILED_PRECONDITION
+	Aborted            Code = C.TF_ABORTED
+	OutOfRange         Code = C.TF_OUT_OF_RANGE
+	Unimplemented      Code = C.TF_UNIMPLEMENTED
+	Internal           Code = C.TF_INTERNAL
+	Unavailable        Code = C.TF_UNAVAILABLE
+	DataLoss           Code = C.TF_DATA_LOSS
+)
+
+// String returns the name of the code as used in error_codes.proto,
+// e.g. "NOT_FOUND", and implements [fmt.Stringer].
+func (c Code) String() string {
+	return pbs.Code(c).String()
+}
+
+// Error returns the name of the code.
+func (c Code) Error() string {
+	return c.String()
+}
+
+// Error is the error type reported for failed TensorFlow calls.
+//
+// Use errors.As to access its Code or errors.Is to check for a Code:
+//
+//	if errors.Is(err, tf.ResourceExhausted) {
+//		// retry with a smaller batch
+//	}
+type Error struct {
+	Code    Code
+	Message string
+}
+
+// Error returns the error message and implements the error interface.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is reports whether the error matches target. Target matches if it is
+// the Code of e or if it is an *Error with the same Code and either an
+// empty or the same Message.
+func (e *Error) Is(target error) bool {
+	switch t := target.(type) {
+	case Code:
+		return t == e.Code
+	case *Error:
+		return t.Code == e.Code && (t.Message == "" || t.Message == e.Message)
+	}
+	return false
+}
+
+// ErrorCode returns the Code carried by err. It returns OK
+// for a nil error and Unknown for errors not reported by TensorFlow.
+func ErrorCode(err error) Code {
+	if err == nil {
+		return OK
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return Unknown
+}
 
 // status holds error information returned by TensorFlow. We convert all
 // TF statuses to Go errors.
@@ -39,8 +125,8 @@ func (s *status) finalizer() {
 	C.TF_DeleteStatus(s.c)
 }
 
-func (s *status) Code() code {
-	return code(C.TF_GetCode(s.c))
+func (s *status) Code() Code {
+	return Code(C.TF_GetCode(s.c))
 }
 
 func (s *status) String() string {
@@ -48,20 +134,10 @@ func (s *status) String() string {
 }
 
 // Err converts the status to a Go error and returns nil if the status is OK.
+// The returned *Error is a snapshot, so it is not affected by later uses of s.
 func (s *status) Err() error {
-	if s == nil || s.Code() == C.TF_OK {
+	if s == nil || s.Code() == OK {
 		return nil
 	}
-	return (*statusError)(s)
-}
-
-// statusError is distinct from status because it fulfills the error interface.
-// status itself may have a TF_OK code and is not always considered an error.
-//
-// TODO(jhseu): Make public, rename to Error, and provide a way for users to
-// check status codes.
-type statusError status
-
-func (s *statusError) Error() string {
-	return (*status)(s).String()
+	return &Error{Code: s.Code(), Message: s.String()}
 }
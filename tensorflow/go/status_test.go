@@ -0,0 +1,82 @@
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tensorflow
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestErrorCode(t *testing.T) {
+	g := NewGraph()
+	inp := _Placeholder(g, "input", Float)
+	s, err := NewSession(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// fetching an unfed placeholder fails with an invalid argument
+	_, err = s.Run(nil, []Output{inp}, nil)
+	if err == nil {
+		t.Fatal("fetching an unfed placeholder must fail")
+	}
+	var tfErr *Error
+	if !errors.As(err, &tfErr) {
+		t.Fatalf("Got error of type %T, want *Error", err)
+	}
+	if tfErr.Code != InvalidArgument {
+		t.Errorf("Got code %v, want %v", tfErr.Code, InvalidArgument)
+	}
+	if got := ErrorCode(err); got != InvalidArgument {
+		t.Errorf("Got ErrorCode %v, want %v", got, InvalidArgument)
+	}
+	wrapped := fmt.Errorf("wrapped: %w", err)
+	if !errors.Is(wrapped, InvalidArgument) {
+		t.Errorf("errors.Is(%v, %v) = false, want true", wrapped, InvalidArgument)
+	}
+	if errors.Is(wrapped, NotFound) {
+		t.Errorf("errors.Is(%v, %v) = true, want false", wrapped, NotFound)
+	}
+	if !errors.Is(wrapped, &Error{Code: InvalidArgument}) {
+		t.Errorf("errors.Is(%v, &Error{Code: %v}) = false, want true", wrapped, InvalidArgument)
+	}
+}
+
+func TestErrorCodeString(t *testing.T) {
+	tests := []struct {
+		code Code
+		want string
+	}{
+		{OK, "OK"},
+		{NotFound, "NOT_FOUND"},
+		{ResourceExhausted, "RESOURCE_EXHAUSTED"},
+		{Unauthenticated, "UNAUTHENTICATED"},
+	}
+	for _, test := range tests {
+		if got := test.code.String(); got != test.want {
+			t.Errorf("Got %q, want %q", got, test.want)
+		}
+	}
+	if got := ErrorCode(nil); got != OK {
+		t.Errorf("Got ErrorCode(nil) = %v, want %v", got, OK)
+	}
+	if got := ErrorCode(errors.New("not from TensorFlow")); got != Unknown {
+		t.Errorf("Got ErrorCode(non-TF error) = %v, want %v", got, Unknown)
+	}
+}
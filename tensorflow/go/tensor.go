@@ -25,6 +25,14 @@ void toNewTString(_GoString_ gstr, TF_TString *tstr) {
     TF_TString_Init(tstr);
     TF_TString_Copy(tstr, _GoStringPtr(gstr), _GoStringLen(gstr));
 }
+
+extern void goDeallocateTensorBuffer(void* data, size_t len, void* arg);
+
+TF_Tensor* newTensorFromGoBuffer(TF_DataType dtype, const int64_t* dims, int num_dims,
+                                 void* data, size_t len, uintptr_t handle) {
+    return TF_NewTensor(dtype, dims, num_dims, data, len,
+                        goDeallocateTensorBuffer, (void*)handle);
+}
 */
 import "C"
 
@@ -35,6 +43,7 @@ import (
 	"math/bits"
 	"reflect"
 	"runtime"
+	"runtime/cgo"
 	"unsafe"
 )
 
@@ -137,6 +146,45 @@ func NewTensor(value interface{}) (*Tensor, error) {
 	return t, nil
 }
 
+// NewTensorFromBytes creates a Tensor with the provided numeric type and
+// shape that uses data as its buffer instead of copying it. The data must
+// contain the elements in row-major order and in the native byte order.
+//
+// The buffer is pinned until TensorFlow releases the Tensor, so data must
+// not be modified while the Tensor is in use. TensorFlow requires aligned
+// buffers and copies data if it is not aligned to 64 bytes, which is e.g.
+// guaranteed for large slices allocated by the Go runtime. Pinning the buffer
+// requires Go 1.21, so data is always copied when built with older versions.
+func NewTensorFromBytes(dataType DataType, shape []int64, data []byte) (*Tensor, error) {
+	elemSize := int64(C.TF_DataTypeSize(C.TF_DataType(dataType)))
+	if elemSize == 0 {
+		return nil, fmt.Errorf("cannot create a tensor of type %v from bytes", dataType)
+	}
+	for _, dim := range shape {
+		if dim < 0 {
+			return nil, fmt.Errorf("all shape dimentions should be non-negative: %v", shape)
+		}
+	}
+	if nbytes := elemSize * numElements(shape); int64(len(data)) != nbytes {
+		return nil, fmt.Errorf("got %d bytes for a tensor of type %v and shape %v, want %d bytes", len(data), dataType, shape, nbytes)
+	}
+	var shapePtr *C.int64_t
+	if len(shape) > 0 {
+		shapePtr = (*C.int64_t)(unsafe.Pointer(&shape[0]))
+	}
+	if len(data) > 0 {
+		if pinner, ok := pinBuffer(&data[0]); ok {
+			handle := cgo.NewHandle(pinner)
+			c := C.newTensorFromGoBuffer(C.TF_DataType(dataType), shapePtr, C.int(len(shape)),
+				unsafe.Pointer(&data[0]), C.size_t(len(data)), C.uintptr_t(handle))
+			return newTensorFromC(c), nil
+		}
+	}
+	c := C.TF_AllocateTensor(C.TF_DataType(dataType), shapePtr, C.int(len(shape)), C.size_t(len(data)))
+	copy(tensorData(c), data)
+	return newTensorFromC(c), nil
+}
+
 // isAllArray returns true if type is a primitive type or an array of primitive
 // types or an array of ... etc.. When this is true the data we want is
 // contiguous in RAM.
@@ -289,10 +337,19 @@ func (t *Tensor) MustReshape(newShape ...int64) *Tensor {
 // For example:
 // Tensor(int64, 0): int64
 // Tensor(float64, 3): [][][]float64
+// Tensor(resource, 0): ResourceHandle
+// Tensor(variant, 1): []VariantValue
 func (t *Tensor) Value() interface{} {
-	raw := tensorData(t.c)
 	shape := t.Shape()
 	dt := t.DataType()
+	if _, ok := opaqueTypes[dt]; ok {
+		slice, err := decodeOpaqueTensor(t)
+		if err != nil {
+			panic(err)
+		}
+		return shapeSlice(slice, shape).Interface()
+	}
+	raw := tensorData(t.c)
 	return decodeTensor(raw, shape, dt).Interface()
 }
 
@@ -301,10 +358,7 @@ func decodeTensor(raw []byte, shape []int64, dt DataType) reflect.Value {
 	// copy the data in.
 	n := int(numElements(shape))
 
-	var (
-		slice reflect.Value
-		typ   reflect.Type
-	)
+	var slice reflect.Value
 	switch dt {
 	case String:
 		strs, err := decodeOneDimString(raw, n)
@@ -312,16 +366,12 @@ func decodeTensor(raw []byte, shape []int64, dt DataType) reflect.Value {
 			panic(bug("unable to decode string with shape %v: %v", shape, err))
 		}
 		slice = reflect.ValueOf(strs)
-		typ = slice.Type()
-	case Resource:
-		bug("decoding Resource tensor is only supported within the graph for now.")
-	case Variant:
-		bug("decoding Variant tensor is only supported within the graph for now.")
+	case Resource, Variant:
+		panic(bug("%v tensors must be decoded with decodeOpaqueTensor", dt))
 	default:
-		typ = typeForDataType(dt)
+		typ := typeForDataType(dt)
 		l := n * int(typ.Size())
-		typ = reflect.SliceOf(typ)
-		slice = reflect.MakeSlice(typ, n, n)
+		slice = reflect.MakeSlice(reflect.SliceOf(typ), n, n)
 		baseBytes := *(*[]byte)(unsafe.Pointer(&sliceHeader{
 			Data: unsafe.Pointer(slice.Pointer()),
 			Len:  l,
@@ -329,6 +379,14 @@ func decodeTensor(raw []byte, shape []int64, dt DataType) reflect.Value {
 		}))
 		copy(baseBytes, raw)
 	}
+	return shapeSlice(slice, shape)
+}
+
+// shapeSlice arranges the elements of the one-dimensional slice into nested
+// slices following the shape.
+func shapeSlice(slice reflect.Value, shape []int64) reflect.Value {
+	n := slice.Len()
+	typ := slice.Type()
 
 	// Now we have the data in place in the base slice we can add the
 	// dimensions. We want to walk backwards through the shape. If the shape is
@@ -450,8 +508,13 @@ var types = []struct {
 	{reflect.TypeOf(false), C.TF_BOOL},
 	{reflect.TypeOf(uint16(0)), C.TF_UINT16},
 	{reflect.TypeOf(complex(float64(0), float64(0))), C.TF_COMPLEX128},
-	// TODO(apassos): support DT_RESOURCE representation in go.
-	// TODO(keveman): support DT_VARIANT representation in go.
+	// The following types share their kind with the types above,
+	// so they are only matched by their exact Go type.
+	{reflect.TypeOf(Float16(0)), C.TF_HALF},
+	{reflect.TypeOf(BFloat16(0)), C.TF_BFLOAT16},
+	{reflect.TypeOf(Float8E5M2(0)), C.TF_FLOAT8_E5M2},
+	{reflect.TypeOf(Float8E4M3FN(0)), C.TF_FLOAT8_E4M3FN},
+	// Resource and variant tensors are represented by the opaqueTypes.
 }
 
 // shapeAndDataTypeOf returns the data type and shape of the Tensor
@@ -475,6 +538,11 @@ func shapeAndDataTypeOf(val reflect.Value) (shape []int64, dt DataType, err erro
 		}
 		typ = typ.Elem()
 	}
+	for _, t := range types {
+		if typ == t.typ {
+			return shape, DataType(t.dataType), nil
+		}
+	}
 	for _, t := range types {
 		if typ.Kind() == t.typ.Kind() {
 			return shape, DataType(t.dataType), nil
@@ -597,7 +665,7 @@ func isTensorSerializable(dataType DataType) error {
 	// serialization and deserialization of Tensors.  Till then capitalize
 	// on knowledge of the implementation for numeric types.
 	switch dataType {
-	case Float, Double, Int32, Uint8, Int16, Int8, Complex, Int64, Bool, Quint8, Qint32, Bfloat16, Qint16, Quint16, Uint16, Complex128, Half, Float8e5m2, Float8e4m3fn:
+	case Float, Double, Int32, Uint8, Int16, Int8, Complex, Int64, Bool, Quint8, Qint32, Bfloat16, Qint16, Quint16, Uint16, Complex128, Half, Uint32, Uint64, Float8e5m2, Float8e4m3fn:
 		return nil
 	default:
 		return fmt.Errorf("serialization of tensors with the DataType %d is not yet supported, see https://github.com/tensorflow/tensorflow/issues/6003", dataType)
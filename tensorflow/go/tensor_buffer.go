@@ -0,0 +1,141 @@
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tensorflow
+
+// The preamble of a file with exported functions must not contain
+// definitions, so the C helpers using them are defined in tensor.go.
+
+// #include <stddef.h>
+// #include "tensorflow/c/tf_datatype.h"
+import "C"
+
+import (
+	"fmt"
+	"runtime/cgo"
+	"unsafe"
+)
+
+// unpinner releases a buffer pinned by pinBuffer.
+type unpinner interface {
+	Unpin()
+}
+
+// goDeallocateTensorBuffer is the deallocator of tensors created by
+// NewTensorFromBytes. It unpins the Go buffer once TensorFlow releases it.
+//
+//export goDeallocateTensorBuffer
+func goDeallocateTensorBuffer(data unsafe.Pointer, length C.size_t, arg unsafe.Pointer) {
+	h := cgo.Handle(uintptr(arg))
+	h.Value().(unpinner).Unpin()
+	h.Delete()
+}
+
+// The following accessors return slices that alias the buffer of a Tensor.
+// Reading or writing them does not copy the tensor contents, but they are only
+// valid as long as the Tensor is reachable, e.g. by using runtime.KeepAlive.
+
+// Bytes returns the contents of a Tensor with a numeric DataType as a byte
+// slice aliasing the tensor buffer. It panics for other types, e.g. String.
+func (t *Tensor) Bytes() []byte {
+	if C.TF_DataTypeSize(C.TF_DataType(t.DataType())) == 0 {
+		panic(fmt.Errorf("cannot access the bytes of a tensor of type %v", t.DataType()))
+	}
+	return tensorData(t.c)
+}
+
+// Float32s returns the elements of a Float tensor as a slice aliasing its buffer.
+// It panics if the tensor has another type.
+func (t *Tensor) Float32s() []float32 { return tensorView[float32](t, Float) }
+
+// Float64s returns the elements of a Double tensor as a slice aliasing its buffer.
+// It panics if the tensor has another type.
+func (t *Tensor) Float64s() []float64 { return tensorView[float64](t, Double) }
+
+// Int8s returns the elements of an Int8 tensor as a slice aliasing its buffer.
+// It panics if the tensor has another type.
+func (t *Tensor) Int8s() []int8 { return tensorView[int8](t, Int8) }
+
+// Int16s returns the elements of an Int16 tensor as a slice aliasing its buffer.
+// It panics if the tensor has another type.
+func (t *Tensor) Int16s() []int16 { return tensorView[int16](t, Int16) }
+
+// Int32s returns the elements of an Int32 tensor as a slice aliasing its buffer.
+// It panics if the tensor has another type.
+func (t *Tensor) Int32s() []int32 { return tensorView[int32](t, Int32) }
+
+// Int64s returns the elements of an Int64 tensor as a slice aliasing its buffer.
+// It panics if the tensor has another type.
+func (t *Tensor) Int64s() []int64 { return tensorView[int64](t, Int64) }
+
+// Uint8s returns the elements of a Uint8 tensor as a slice aliasing its buffer.
+// It panics if the tensor has another type.
+func (t *Tensor) Uint8s() []uint8 { return tensorView[uint8](t, Uint8) }
+
+// Uint16s returns the elements of a Uint16 tensor as a slice aliasing its buffer.
+// It panics if the tensor has another type.
+func (t *Tensor) Uint16s() []uint16 { return tensorView[uint16](t, Uint16) }
+
+// Uint32s returns the elements of a Uint32 tensor as a slice aliasing its buffer.
+// It panics if the tensor has another type.
+func (t *Tensor) Uint32s() []uint32 { return tensorView[uint32](t, Uint32) }
+
+// Uint64s returns the elements of a Uint64 tensor as a slice aliasing its buffer.
+// It panics if the tensor has another type.
+func (t *Tensor) Uint64s() []uint64 { return tensorView[uint64](t, Uint64) }
+
+// Bools returns the elements of a Bool tensor as a slice aliasing its buffer.
+// It panics if the tensor has another type.
+func (t *Tensor) Bools() []bool { return tensorView[bool](t, Bool) }
+
+// Complex64s returns the elements of a Complex64 tensor as a slice aliasing its buffer.
+// It panics if the tensor has another type.
+func (t *Tensor) Complex64s() []complex64 { return tensorView[complex64](t, Complex64) }
+
+// Complex128s returns the elements of a Complex128 tensor as a slice aliasing its buffer.
+// It panics if the tensor has another type.
+func (t *Tensor) Complex128s() []complex128 { return tensorView[complex128](t, Complex128) }
+
+// Float16s returns the elements of a Half tensor as a slice aliasing its buffer.
+// It panics if the tensor has another type.
+func (t *Tensor) Float16s() []Float16 { return tensorView[Float16](t, Half) }
+
+// BFloat16s returns the elements of a Bfloat16 tensor as a slice aliasing its buffer.
+// It panics if the tensor has another type.
+func (t *Tensor) BFloat16s() []BFloat16 { return tensorView[BFloat16](t, Bfloat16) }
+
+// Float8E5M2s returns the elements of a Float8e5m2 tensor as a slice aliasing its buffer.
+// It panics if the tensor has another type.
+func (t *Tensor) Float8E5M2s() []Float8E5M2 { return tensorView[Float8E5M2](t, Float8e5m2) }
+
+// Float8E4M3FNs returns the elements of a Float8e4m3fn tensor as a slice aliasing its buffer.
+// It panics if the tensor has another type.
+func (t *Tensor) Float8E4M3FNs() []Float8E4M3FN { return tensorView[Float8E4M3FN](t, Float8e4m3fn) }
+
+// tensorView returns the elements of t as a slice of type T aliasing
+// the tensor buffer after checking that t has the expected DataType.
+func tensorView[T any](t *Tensor, dataType DataType) []T {
+	if dt := t.DataType(); dt != dataType {
+		panic(fmt.Errorf("cannot access a tensor of type %v as %v", dt, dataType))
+	}
+	raw := tensorData(t.c)
+	var elem T
+	n := len(raw) / int(unsafe.Sizeof(elem))
+	if n == 0 {
+		return []T{}
+	}
+	return unsafe.Slice((*T)(unsafe.Pointer(&raw[0])), n)
+}
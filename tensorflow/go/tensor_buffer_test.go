@@ -0,0 +1,126 @@
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tensorflow
+
+import (
+	"reflect"
+	"runtime"
+	"testing"
+	"unsafe"
+)
+
+func TestNewTensorFromBytes(t *testing.T) {
+	// a large buffer is page aligned by the Go runtime and used without copy
+	values := make([]float32, 1<<14)
+	for i := range values {
+		values[i] = float32(i)
+	}
+	data := unsafe.Slice((*byte)(unsafe.Pointer(&values[0])), 4*len(values))
+	tensor, err := NewTensorFromBytes(Float, []int64{1 << 7, 1 << 7}, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tensor.Shape(), []int64{1 << 7, 1 << 7}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got shape %v, want %v", got, want)
+	}
+	view := tensor.Float32s()
+	if !reflect.DeepEqual(view, values) {
+		t.Errorf("tensor contents differ from its buffer")
+	}
+	// the view aliases the tensor buffer
+	view[3] = -3
+	if got := tensor.Value().([][]float32)[0][3]; got != -3 {
+		t.Errorf("Got %v after writing to the view, want -3", got)
+	}
+	if got := tensor.Bytes(); len(got) != len(data) {
+		t.Errorf("Got %d bytes, want %d", len(got), len(data))
+	}
+	runtime.KeepAlive(tensor)
+
+	// the tensor outlives the Go references to its buffer
+	tensor, err = NewTensorFromBytes(Int16, []int64{2}, []byte{1, 0, 2, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	runtime.GC()
+	if got, want := tensor.Value(), []int16{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %v, want %v", got, want)
+	}
+
+	empty, err := NewTensorFromBytes(Double, []int64{3, 0}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(empty.Float64s()); got != 0 {
+		t.Errorf("Got %d elements for an empty tensor, want 0", got)
+	}
+}
+
+func TestNewTensorFromBytesErrors(t *testing.T) {
+	tests := []struct {
+		dataType DataType
+		shape    []int64
+		data     []byte
+	}{
+		{String, []int64{1}, make([]byte, 24)},
+		{Int32, []int64{2}, make([]byte, 7)},
+		{Int32, []int64{-1}, make([]byte, 4)},
+	}
+	for _, test := range tests {
+		if _, err := NewTensorFromBytes(test.dataType, test.shape, test.data); err == nil {
+			t.Errorf("NewTensorFromBytes(%v, %v, %d bytes) must fail", test.dataType, test.shape, len(test.data))
+		}
+	}
+}
+
+func TestTensorViews(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		view  func(*Tensor) interface{}
+	}{
+		{[]float32{1, 2}, func(t *Tensor) interface{} { return t.Float32s() }},
+		{[]float64{1, 2}, func(t *Tensor) interface{} { return t.Float64s() }},
+		{[]int8{1, 2}, func(t *Tensor) interface{} { return t.Int8s() }},
+		{[]int16{1, 2}, func(t *Tensor) interface{} { return t.Int16s() }},
+		{[]int32{1, 2}, func(t *Tensor) interface{} { return t.Int32s() }},
+		{[]int64{1, 2}, func(t *Tensor) interface{} { return t.Int64s() }},
+		{[]uint8{1, 2}, func(t *Tensor) interface{} { return t.Uint8s() }},
+		{[]uint16{1, 2}, func(t *Tensor) interface{} { return t.Uint16s() }},
+		{[]uint32{1, 2}, func(t *Tensor) interface{} { return t.Uint32s() }},
+		{[]uint64{1, 2}, func(t *Tensor) interface{} { return t.Uint64s() }},
+		{[]bool{true, false}, func(t *Tensor) interface{} { return t.Bools() }},
+		{[]complex64{1, 2i}, func(t *Tensor) interface{} { return t.Complex64s() }},
+		{[]complex128{1, 2i}, func(t *Tensor) interface{} { return t.Complex128s() }},
+	}
+	for _, test := range tests {
+		tensor, err := NewTensor(test.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := test.view(tensor); !reflect.DeepEqual(got, test.value) {
+			t.Errorf("Got %v, want %v", got, test.value)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("accessing an Int32 tensor as float32 must panic")
+		}
+	}()
+	tensor, _ := NewTensor([]int32{1})
+	tensor.Float32s()
+}
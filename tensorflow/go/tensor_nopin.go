@@ -0,0 +1,25 @@
+//go:build !go1.21
+
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tensorflow
+
+// pinBuffer cannot pin buffers before Go 1.21, which introduced
+// runtime.Pinner, so NewTensorFromBytes copies the data instead.
+func pinBuffer(p *byte) (unpinner, bool) {
+	return nil, false
+}
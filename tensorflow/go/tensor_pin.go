@@ -0,0 +1,29 @@
+//go:build go1.21
+
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tensorflow
+
+import "runtime"
+
+// pinBuffer pins the buffer starting at p, so that TensorFlow can use it
+// after the cgo call returns.
+func pinBuffer(p *byte) (unpinner, bool) {
+	pinner := new(runtime.Pinner)
+	pinner.Pin(p)
+	return pinner, true
+}
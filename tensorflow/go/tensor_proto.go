@@ -0,0 +1,294 @@
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tensorflow
+
+// #include <stdlib.h>
+// #include "tensorflow/c/c_api.h"
+import "C"
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"runtime"
+	"unsafe"
+
+	"github.com/hdu-hh/tensorflow/tensorflow/go/pbs"
+	"google.golang.org/protobuf/proto"
+)
+
+// ResourceHandle is the Go representation of an element of a Resource tensor,
+// e.g. a handle to a variable or to a dataset iterator.
+// It mirrors the [pbs.ResourceHandleProto] protocol buffer.
+type ResourceHandle struct {
+	// Device is the name of the device that holds the resource.
+	Device string
+	// Container is the container in which the resource is placed.
+	Container string
+	// Name is the unique name of the resource within its container.
+	Name string
+	// HashCode is the hash code of the C++ type of the resource.
+	HashCode uint64
+	// MaybeTypeName is the demangled C++ type name of the resource,
+	// it is only available in debug builds of TensorFlow.
+	MaybeTypeName string
+	// DtypesAndShapes describes the tensors held by the resource,
+	// e.g. the data type and shape of a variable.
+	DtypesAndShapes []DtypeAndShape
+}
+
+// DtypeAndShape describes a tensor held by a resource.
+type DtypeAndShape struct {
+	DataType DataType
+	Shape    Shape
+}
+
+// VariantValue is the Go representation of an element of a Variant tensor,
+// e.g. a tensor list or a dataset. It mirrors the serialized form
+// [pbs.VariantTensorDataProto] of the C++ object held by the element.
+type VariantValue struct {
+	// TypeName is the name of the C++ type held by the variant.
+	TypeName string
+	// Metadata is the type-specific encoded metadata of the value.
+	Metadata []byte
+	// Tensors are the tensors held by the value.
+	Tensors []*Tensor
+}
+
+// opaqueTypes holds the element types of the tensors whose elements are C++
+// objects instead of plain data.
+var opaqueTypes = map[DataType]reflect.Type{
+	Resource: reflect.TypeOf(ResourceHandle{}),
+	Variant:  reflect.TypeOf(VariantValue{}),
+}
+
+// decodeOpaqueTensor decodes a Resource or Variant tensor into a flat slice.
+//
+// The C API provides no direct access to the C++ objects held by such tensors,
+// so the tensor is converted into a TensorProto by attaching it as the value
+// of a constant to a scratch graph and reading that attribute back.
+func decodeOpaqueTensor(t *Tensor) (reflect.Value, error) {
+	pb, err := tensorProtoViaGraph(t)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	n := int(numElements(t.Shape()))
+	slice := reflect.MakeSlice(reflect.SliceOf(opaqueTypes[t.DataType()]), n, n)
+	switch t.DataType() {
+	case Resource:
+		handles := pb.GetResourceHandleVal()
+		if content := pb.GetTensorContent(); len(content) > 0 {
+			handles = make([]*pbs.ResourceHandleProto, n)
+			err = decodeMessageList(content, n, func(i int, b []byte) error {
+				handles[i] = &pbs.ResourceHandleProto{}
+				return proto.Unmarshal(b, handles[i])
+			})
+		}
+		for i := 0; i < n && i < len(handles) && err == nil; i++ {
+			slice.Index(i).Set(reflect.ValueOf(resourceHandleFromProto(handles[i])))
+		}
+	case Variant:
+		variants := pb.GetVariantVal()
+		if content := pb.GetTensorContent(); len(content) > 0 {
+			variants = make([]*pbs.VariantTensorDataProto, n)
+			err = decodeMessageList(content, n, func(i int, b []byte) error {
+				variants[i] = &pbs.VariantTensorDataProto{}
+				return proto.Unmarshal(b, variants[i])
+			})
+		}
+		for i := 0; i < n && i < len(variants) && err == nil; i++ {
+			var v VariantValue
+			if v, err = variantValueFromProto(variants[i]); err == nil {
+				slice.Index(i).Set(reflect.ValueOf(v))
+			}
+		}
+	default:
+		err = fmt.Errorf("tensor of type %v is not opaque", t.DataType())
+	}
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("failed to decode %v tensor: %w", t.DataType(), err)
+	}
+	return slice, nil
+}
+
+// tensorProtoViaGraph lets the TensorFlow runtime serialize t into a
+// TensorProto by reading it back as an attribute of a constant.
+func tensorProtoViaGraph(t *Tensor) (*pbs.TensorProto, error) {
+	g := NewGraph()
+	op, err := g.AddOperation(OpSpec{
+		Type:  "Const",
+		Attrs: map[string]interface{}{"dtype": t.DataType(), "value": t},
+	})
+	if err != nil {
+		return nil, err
+	}
+	cName := C.CString("value")
+	defer C.free(unsafe.Pointer(cName))
+	buf := C.TF_NewBuffer()
+	defer C.TF_DeleteBuffer(buf)
+	status := newStatus()
+	C.TF_OperationGetAttrValueProto(op.c, cName, buf, status.c)
+	if err := status.Err(); err != nil {
+		return nil, err
+	}
+	b, err := getBufferAsSlice(buf)
+	if err != nil {
+		return nil, err
+	}
+	attr := &pbs.AttrValue{}
+	if err := proto.Unmarshal(b, attr); err != nil {
+		return nil, err
+	}
+	return attr.GetTensor(), nil
+}
+
+// decodeMessageList decodes the tensor_content encoding of n serialized
+// messages: n varint lengths followed by the concatenated messages.
+func decodeMessageList(content []byte, n int, decode func(i int, b []byte) error) error {
+	sizes := make([]uint64, n)
+	for i := range sizes {
+		size, l := binary.Uvarint(content)
+		if l <= 0 {
+			return fmt.Errorf("invalid size of element %d", i)
+		}
+		sizes[i] = size
+		content = content[l:]
+	}
+	for i, size := range sizes {
+		if uint64(len(content)) < size {
+			return fmt.Errorf("element %d is truncated", i)
+		}
+		if err := decode(i, content[:size]); err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+		content = content[size:]
+	}
+	return nil
+}
+
+func resourceHandleFromProto(pb *pbs.ResourceHandleProto) ResourceHandle {
+	var dtypesAndShapes []DtypeAndShape
+	for _, ds := range pb.GetDtypesAndShapes() {
+		dtypesAndShapes = append(dtypesAndShapes, DtypeAndShape{
+			DataType: DataType(C.TF_DataType(ds.GetDtype())),
+			Shape:    shapeFromProto(ds.GetShape()),
+		})
+	}
+	return ResourceHandle{
+		Device:          pb.GetDevice(),
+		Container:       pb.GetContainer(),
+		Name:            pb.GetName(),
+		HashCode:        pb.GetHashCode(),
+		MaybeTypeName:   pb.GetMaybeTypeName(),
+		DtypesAndShapes: dtypesAndShapes,
+	}
+}
+
+func variantValueFromProto(pb *pbs.VariantTensorDataProto) (VariantValue, error) {
+	v := VariantValue{
+		TypeName: pb.GetTypeName(),
+		Metadata: pb.GetMetadata(),
+	}
+	for _, tp := range pb.GetTensors() {
+		t, err := NewTensorFromProto(tp)
+		if err != nil {
+			return v, err
+		}
+		v.Tensors = append(v.Tensors, t)
+	}
+	return v, nil
+}
+
+// NewTensorFromProto converts a [pbs.TensorProto] into a Tensor.
+//
+// The values may be given either packed in tensor_content or in the repeated
+// field matching the data type, e.g. float_val or string_val. When the
+// repeated field holds fewer values than the shape requires, the last value is
+// repeated to fill the tensor, so a single value initializes all elements.
+func NewTensorFromProto(pb *pbs.TensorProto) (*Tensor, error) {
+	b, err := proto.Marshal(pb)
+	if err != nil {
+		return nil, err
+	}
+	buf := newBufferFromSlice(b)
+	defer C.TF_DeleteBuffer(buf)
+	// TF_TensorFromProto replaces the data type and shape of the
+	// pre-allocated tensor with the ones from the protocol buffer.
+	c := C.TF_AllocateTensor(C.TF_FLOAT, nil, 0, 0)
+	status := newStatus()
+	C.TF_TensorFromProto(buf, c, status.c)
+	if err := status.Err(); err != nil {
+		C.TF_DeleteTensor(c)
+		return nil, fmt.Errorf("invalid tensor proto: %w", err)
+	}
+	return newTensorFromC(c), nil
+}
+
+// ToProto converts the Tensor into a [pbs.TensorProto].
+//
+// Numeric values are packed into tensor_content and strings are stored in
+// string_val. Resource and Variant tensors are serialized by the TensorFlow
+// runtime into resource_handle_val and variant_val or into tensor_content.
+func (t *Tensor) ToProto() (*pbs.TensorProto, error) {
+	dt := t.DataType()
+	if _, ok := opaqueTypes[dt]; ok {
+		return tensorProtoViaGraph(t)
+	}
+	pb := &pbs.TensorProto{
+		Dtype:       pbs.DataType(dt),
+		TensorShape: shapeToProto(t.Shape()),
+	}
+	raw := tensorData(t.c)
+	if dt == String {
+		n := int(numElements(t.Shape()))
+		tstrs := (*(*[]C.TF_TString)(unsafe.Pointer(&raw)))[:n]
+		pb.StringVal = make([][]byte, n)
+		for i := range tstrs {
+			data := C.TF_TString_GetDataPointer(&tstrs[i])
+			size := C.TF_TString_GetSize(&tstrs[i])
+			pb.StringVal[i] = C.GoBytes(unsafe.Pointer(data), C.int(size))
+		}
+		runtime.KeepAlive(t)
+		return pb, nil
+	}
+	if err := isTensorSerializable(dt); err != nil {
+		return nil, err
+	}
+	// tensor_content holds the in-memory representation of the values
+	pb.TensorContent = append([]byte(nil), raw...)
+	runtime.KeepAlive(t)
+	return pb, nil
+}
+
+func shapeFromProto(pb *pbs.TensorShapeProto) Shape {
+	if pb.GetUnknownRank() {
+		return Shape{}
+	}
+	dims := make([]int64, len(pb.GetDim()))
+	for i, d := range pb.GetDim() {
+		dims[i] = d.GetSize()
+	}
+	return MakeShape(dims...)
+}
+
+func shapeToProto(shape []int64) *pbs.TensorShapeProto {
+	dims := make([]*pbs.TensorShapeProto_Dim, len(shape))
+	for i, size := range shape {
+		dims[i] = &pbs.TensorShapeProto_Dim{Size: size}
+	}
+	return &pbs.TensorShapeProto{Dim: dims}
+}
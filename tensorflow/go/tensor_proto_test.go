@@ -0,0 +1,254 @@
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tensorflow
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hdu-hh/tensorflow/tensorflow/go/pbs"
+	"google.golang.org/protobuf/proto"
+)
+
+func _VarHandle(g *Graph, name string, dt DataType, shape Shape) Output {
+	op, err := g.AddOperation(OpSpec{
+		Type: "VarHandleOp",
+		Name: name,
+		Attrs: map[string]interface{}{
+			"dtype":       dt,
+			"shape":       shape,
+			"shared_name": name,
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return op.Output(0)
+}
+
+func fetchOne(t *testing.T, g *Graph, fetch Output) *Tensor {
+	t.Helper()
+	s, err := NewSession(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	fetched, err := s.Run(nil, []Output{fetch}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fetched[0]
+}
+
+func TestResourceTensorValue(t *testing.T) {
+	g := NewGraph()
+	v := _VarHandle(g, "v", Float, MakeShape(2, 3))
+	tensor := fetchOne(t, g, v)
+	if got := tensor.DataType(); got != Resource {
+		t.Fatalf("Got type %v, want %v", got, Resource)
+	}
+	handle, ok := tensor.Value().(ResourceHandle)
+	if !ok {
+		t.Fatalf("Got %T, want ResourceHandle", tensor.Value())
+	}
+	if handle.Name != "v" {
+		t.Errorf("Got name %q, want %q", handle.Name, "v")
+	}
+	if handle.Device == "" {
+		t.Errorf("Device of the resource is not set")
+	}
+	want := []DtypeAndShape{{Float, MakeShape(2, 3)}}
+	if !reflect.DeepEqual(handle.DtypesAndShapes, want) {
+		t.Errorf("Got %v, want %v", handle.DtypesAndShapes, want)
+	}
+
+	// multiple resource handles are encoded differently from a single one
+	g = NewGraph()
+	pack, err := g.AddOperation(OpSpec{
+		Type:  "Pack",
+		Input: []Input{OutputList{_VarHandle(g, "a", Int32, ScalarShape()), _VarHandle(g, "b", Int64, ScalarShape())}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handles, ok := fetchOne(t, g, pack.Output(0)).Value().([]ResourceHandle)
+	if !ok || len(handles) != 2 {
+		t.Fatalf("Got %#v, want two resource handles", handles)
+	}
+	if handles[0].Name != "a" || handles[1].Name != "b" {
+		t.Errorf("Got names %q and %q, want %q and %q", handles[0].Name, handles[1].Name, "a", "b")
+	}
+}
+
+func TestVariantTensorValue(t *testing.T) {
+	g := NewGraph()
+	list, err := g.AddOperation(OpSpec{
+		Type: "TensorListFromTensor",
+		Input: []Input{
+			_Const(g, "elements", [][]float32{{1, 2}, {3, 4}}),
+			_Const(g, "element_shape", []int32{2}),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tensor := fetchOne(t, g, list.Output(0))
+	if got := tensor.DataType(); got != Variant {
+		t.Fatalf("Got type %v, want %v", got, Variant)
+	}
+	value, ok := tensor.Value().(VariantValue)
+	if !ok {
+		t.Fatalf("Got %T, want VariantValue", tensor.Value())
+	}
+	if want := "tensorflow::TensorList"; value.TypeName != want {
+		t.Errorf("Got type name %q, want %q", value.TypeName, want)
+	}
+	if len(value.Tensors) != 2 {
+		t.Fatalf("Got %d tensors, want 2", len(value.Tensors))
+	}
+	for i, want := range [][]float32{{1, 2}, {3, 4}} {
+		if got := value.Tensors[i].Value(); !reflect.DeepEqual(got, want) {
+			t.Errorf("Got tensor %d = %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestTensorProtoRoundtrip(t *testing.T) {
+	values := []interface{}{
+		float32(1.5),
+		[]float64{1, -2, 3e100},
+		[][]int32{{1, 2, 3}, {4, 5, 6}},
+		[]int64{},
+		[]bool{true, false},
+		[]complex64{1 + 2i},
+		[]Float16{NewFloat16(1), NewFloat16(0.5)},
+		"a string",
+		[][]string{{"a", ""}, {"\x00binary\xff", "d"}},
+	}
+	for _, value := range values {
+		tensor, err := NewTensor(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pb, err := tensor.ToProto()
+		if err != nil {
+			t.Errorf("ToProto(%v): %v", value, err)
+			continue
+		}
+		// the proto must survive its wire format
+		pb = pbs.MustUnmarshal(pbs.MustMarshal(pb), &pbs.TensorProto{})
+		got, err := NewTensorFromProto(pb)
+		if err != nil {
+			t.Errorf("NewTensorFromProto(%v): %v", pb, err)
+			continue
+		}
+		if !reflect.DeepEqual(got.Shape(), tensor.Shape()) {
+			t.Errorf("Got shape %v, want %v", got.Shape(), tensor.Shape())
+		}
+		if !reflect.DeepEqual(got.Value(), value) {
+			t.Errorf("Got %v, want %v", got.Value(), value)
+		}
+	}
+}
+
+func TestTensorProtoStrings(t *testing.T) {
+	tensor, err := NewTensor([]string{"hello", "world"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pb, err := tensor.ToProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &pbs.TensorProto{
+		Dtype:       pbs.DataType_DT_STRING,
+		TensorShape: &pbs.TensorShapeProto{Dim: []*pbs.TensorShapeProto_Dim{{Size: 2}}},
+		StringVal:   [][]byte{[]byte("hello"), []byte("world")},
+	}
+	if !proto.Equal(pb, want) {
+		t.Errorf("Got %v, want %v", pb, want)
+	}
+}
+
+func TestNewTensorFromProto(t *testing.T) {
+	shape := func(dims ...int64) *pbs.TensorShapeProto {
+		pb := &pbs.TensorShapeProto{}
+		for _, d := range dims {
+			pb.Dim = append(pb.Dim, &pbs.TensorShapeProto_Dim{Size: d})
+		}
+		return pb
+	}
+	tests := []struct {
+		pb   *pbs.TensorProto
+		want interface{}
+	}{
+		{
+			&pbs.TensorProto{Dtype: pbs.DataType_DT_FLOAT, TensorShape: shape(2), FloatVal: []float32{1, 2}},
+			[]float32{1, 2},
+		},
+		{
+			// the last value is broadcast to the remaining elements
+			&pbs.TensorProto{Dtype: pbs.DataType_DT_FLOAT, TensorShape: shape(2, 2), FloatVal: []float32{1, 2}},
+			[][]float32{{1, 2}, {2, 2}},
+		},
+		{
+			&pbs.TensorProto{Dtype: pbs.DataType_DT_INT64, TensorShape: shape(3), Int64Val: []int64{7}},
+			[]int64{7, 7, 7},
+		},
+		{
+			&pbs.TensorProto{Dtype: pbs.DataType_DT_UINT8, TensorShape: shape(), IntVal: []int32{200}},
+			uint8(200),
+		},
+		{
+			&pbs.TensorProto{Dtype: pbs.DataType_DT_HALF, TensorShape: shape(2), HalfVal: []int32{0x3c00, 0xc000}},
+			[]Float16{NewFloat16(1), NewFloat16(-2)},
+		},
+		{
+			&pbs.TensorProto{Dtype: pbs.DataType_DT_COMPLEX64, TensorShape: shape(1), ScomplexVal: []float32{1, 2}},
+			[]complex64{1 + 2i},
+		},
+		{
+			&pbs.TensorProto{Dtype: pbs.DataType_DT_STRING, TensorShape: shape(2), StringVal: [][]byte{[]byte("a"), []byte("b")}},
+			[]string{"a", "b"},
+		},
+		{
+			&pbs.TensorProto{Dtype: pbs.DataType_DT_INT32, TensorShape: shape(2), TensorContent: []byte{1, 0, 0, 0, 2, 0, 0, 0}},
+			[]int32{1, 2},
+		},
+		{
+			// no values means zero values
+			&pbs.TensorProto{Dtype: pbs.DataType_DT_BOOL, TensorShape: shape(2)},
+			[]bool{false, false},
+		},
+	}
+	for _, test := range tests {
+		tensor, err := NewTensorFromProto(test.pb)
+		if err != nil {
+			t.Errorf("NewTensorFromProto(%v): %v", test.pb, err)
+			continue
+		}
+		if got := tensor.Value(); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("NewTensorFromProto(%v) = %v, want %v", test.pb, got, test.want)
+		}
+	}
+
+	// too many values
+	pb := &pbs.TensorProto{Dtype: pbs.DataType_DT_FLOAT, TensorShape: shape(1), FloatVal: []float32{1, 2}}
+	if _, err := NewTensorFromProto(pb); err == nil {
+		t.Errorf("NewTensorFromProto(%v) did not fail", pb)
+	}
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tensorflow
+
+// #include "tensorflow/c/c_api.h"
+import "C"
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"unsafe"
+)
+
+// At returns the element of the Tensor at the given index, e.g. a float32
+// for a Float tensor or a string for a String tensor. It panics if the number
+// of indices does not match the rank of the Tensor or an index is out of range.
+func (t *Tensor) At(idx ...int64) interface{} {
+	if len(idx) != len(t.shape) {
+		panic(fmt.Errorf("cannot index a tensor of shape %v with %d indices", t.shape, len(idx)))
+	}
+	var offset int64
+	for i, n := range t.shape {
+		if idx[i] < 0 || idx[i] >= n {
+			panic(fmt.Errorf("index %v is out of range for a tensor of shape %v", idx, t.shape))
+		}
+		offset = offset*n + idx[i]
+	}
+	elemSize, err := elementSize(t.DataType())
+	if err != nil {
+		panic(err)
+	}
+	ptr := unsafe.Add(unsafe.Pointer(C.TF_TensorData(t.c)), offset*elemSize)
+	defer runtime.KeepAlive(t)
+	if t.DataType() == String {
+		tstr := (*C.TF_TString)(ptr)
+		return C.GoStringN(C.TF_TString_GetDataPointer(tstr), C.int(C.TF_TString_GetSize(tstr)))
+	}
+	return reflect.NewAt(typeForDataType(t.DataType()), ptr).Elem().Interface()
+}
+
+// Slice returns a copy of the elements of the Tensor with the indices
+// start <= i < end in the dimension dim.
+func (t *Tensor) Slice(dim int, start, end int64) (*Tensor, error) {
+	if dim < 0 || dim >= len(t.shape) {
+		return nil, fmt.Errorf("dimension %d is out of range for a tensor of shape %v", dim, t.shape)
+	}
+	if start < 0 || start > end || end > t.shape[dim] {
+		return nil, fmt.Errorf("slice [%d:%d] is out of range for dimension %d of a tensor of shape %v", start, end, dim, t.shape)
+	}
+	shape := append([]int64(nil), t.shape...)
+	shape[dim] = end - start
+	return t.slice(dim, start, end, shape)
+}
+
+// Split splits the Tensor along the dimension dim into shape[dim] tensors
+// which lack that dimension. E.g. splitting a batch of shape [N, 3] in the
+// dimension 0 results in N tensors of shape [3]. It is the inverse of Stack.
+func (t *Tensor) Split(dim int) ([]*Tensor, error) {
+	if dim < 0 || dim >= len(t.shape) {
+		return nil, fmt.Errorf("dimension %d is out of range for a tensor of shape %v", dim, t.shape)
+	}
+	shape := append(append([]int64(nil), t.shape[:dim]...), t.shape[dim+1:]...)
+	tensors := make([]*Tensor, t.shape[dim])
+	for i := range tensors {
+		var err error
+		if tensors[i], err = t.slice(dim, int64(i), int64(i+1), shape); err != nil {
+			return nil, err
+		}
+	}
+	return tensors, nil
+}
+
+// slice copies the elements with the indices start <= i < end in the
+// dimension dim into a new tensor of the given shape.
+func (t *Tensor) slice(dim int, start, end int64, shape []int64) (*Tensor, error) {
+	s, err := allocateTensor(t.DataType(), shape)
+	if err != nil {
+		return nil, err
+	}
+	outer := numElements(t.shape[:dim])
+	inner := numElements(t.shape[dim+1:])
+	n := (end - start) * inner
+	for o := int64(0); o < outer; o++ {
+		copyElements(s, t, o*n, (o*t.shape[dim]+start)*inner, n)
+	}
+	return s, nil
+}
+
+// Concat joins tensors with the same data type along the dimension dim.
+// The tensors must have the same shape except in the dimension dim.
+func Concat(dim int, tensors ...*Tensor) (*Tensor, error) {
+	if len(tensors) == 0 {
+		return nil, fmt.Errorf("no tensors to concatenate")
+	}
+	shapes := make([][]int64, len(tensors))
+	for i, t := range tensors {
+		shapes[i] = t.shape
+	}
+	return concat(dim, tensors, shapes)
+}
+
+// Stack joins tensors with the same data type and shape along a new
+// dimension which is inserted at the index dim. E.g. stacking N tensors of
+// shape [3] in the dimension 0 results in a batch of shape [N, 3].
+func Stack(dim int, tensors ...*Tensor) (*Tensor, error) {
+	if len(tensors) == 0 {
+		return nil, fmt.Errorf("no tensors to stack")
+	}
+	rank := len(tensors[0].shape)
+	if dim < 0 || dim > rank {
+		return nil, fmt.Errorf("dimension %d is out of range for stacking tensors of rank %d", dim, rank)
+	}
+	shapes := make([][]int64, len(tensors))
+	for i, t := range tensors {
+		if !reflect.DeepEqual(t.shape, tensors[0].shape) {
+			return nil, fmt.Errorf("cannot stack tensors of shape %v and %v", tensors[0].shape, t.shape)
+		}
+		shape := append([]int64(nil), t.shape[:dim]...)
+		shape = append(shape, 1)
+		shapes[i] = append(shape, t.shape[dim:]...)
+	}
+	return concat(dim, tensors, shapes)
+}
+
+// concat joins tensors considered to have the given shapes along the
+// dimension dim.
+func concat(dim int, tensors []*Tensor, shapes [][]int64) (*Tensor, error) {
+	dt := tensors[0].DataType()
+	rank := len(shapes[0])
+	if dim < 0 || dim >= rank {
+		return nil, fmt.Errorf("dimension %d is out of range for tensors of shape %v", dim, shapes[0])
+	}
+	shape := append([]int64(nil), shapes[0]...)
+	shape[dim] = 0
+	for i, t := range tensors {
+		if t.DataType() != dt {
+			return nil, fmt.Errorf("cannot join tensors of type %v and %v", dt, t.DataType())
+		}
+		if len(shapes[i]) != rank {
+			return nil, fmt.Errorf("cannot join tensors of shape %v and %v", shapes[0], shapes[i])
+		}
+		for d, n := range shapes[i] {
+			if d != dim && n != shapes[0][d] {
+				return nil, fmt.Errorf("cannot join tensors of shape %v and %v in dimension %d", shapes[0], shapes[i], dim)
+			}
+		}
+		shape[dim] += shapes[i][dim]
+	}
+
+	joined, err := allocateTensor(dt, shape)
+	if err != nil {
+		return nil, err
+	}
+	outer := numElements(shape[:dim])
+	inner := numElements(shape[dim+1:])
+	var offset int64
+	for o := int64(0); o < outer; o++ {
+		for i, t := range tensors {
+			n := shapes[i][dim] * inner
+			copyElements(joined, t, offset, o*n, n)
+			offset += n
+		}
+	}
+	return joined, nil
+}
+
+// elementSize returns the size of a tensor element in bytes.
+func elementSize(dt DataType) (int64, error) {
+	if dt == String {
+		return C.sizeof_TF_TString, nil
+	}
+	size := int64(C.TF_DataTypeSize(C.TF_DataType(dt)))
+	if size == 0 {
+		return 0, fmt.Errorf("tensors of type %v cannot be accessed on the host", dt)
+	}
+	return size, nil
+}
+
+// allocateTensor allocates a tensor whose elements are set by copyElements.
+func allocateTensor(dt DataType, shape []int64) (*Tensor, error) {
+	elemSize, err := elementSize(dt)
+	if err != nil {
+		return nil, err
+	}
+	shape = append([]int64(nil), shape...)
+	var shapePtr *C.int64_t
+	if len(shape) > 0 {
+		shapePtr = (*C.int64_t)(unsafe.Pointer(&shape[0]))
+	}
+	n := numElements(shape)
+	t := &Tensor{
+		c:     C.TF_AllocateTensor(C.TF_DataType(dt), shapePtr, C.int(len(shape)), C.size_t(n*elemSize)),
+		shape: shape,
+	}
+	runtime.SetFinalizer(t, (*Tensor).finalize)
+	if dt == String {
+		tstrs := unsafe.Slice((*C.TF_TString)(C.TF_TensorData(t.c)), n)
+		for i := range tstrs {
+			C.TF_TString_Init(&tstrs[i])
+		}
+	}
+	return t, nil
+}
+
+// copyElements copies n elements starting at the element srcOffset of src
+// to the element dstOffset of dst. Strings are copied instead of sharing
+// their memory.
+func copyElements(dst, src *Tensor, dstOffset, srcOffset, n int64) {
+	if n == 0 {
+		return
+	}
+	if src.DataType() == String {
+		dstStrs := unsafe.Slice((*C.TF_TString)(C.TF_TensorData(dst.c)), dstOffset+n)[dstOffset:]
+		srcStrs := unsafe.Slice((*C.TF_TString)(C.TF_TensorData(src.c)), srcOffset+n)[srcOffset:]
+		for i := range srcStrs {
+			C.TF_TString_Copy(&dstStrs[i], C.TF_TString_GetDataPointer(&srcStrs[i]), C.TF_TString_GetSize(&srcStrs[i]))
+		}
+	} else {
+		elemSize := int64(C.TF_DataTypeSize(C.TF_DataType(src.DataType())))
+		copy(tensorData(dst.c)[dstOffset*elemSize:], tensorData(src.c)[srcOffset*elemSize:(srcOffset+n)*elemSize])
+	}
+	runtime.KeepAlive(dst)
+	runtime.KeepAlive(src)
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2026 The TensorFlow Authors. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tensorflow
+
+import (
+	"reflect"
+	"testing"
+)
+
+func mustTensor(t testing.TB, value interface{}) *Tensor {
+	t.Helper()
+	tensor, err := NewTensor(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tensor
+}
+
+func TestTensorAt(t *testing.T) {
+	tensor := mustTensor(t, [][]int32{{1, 2, 3}, {4, 5, 6}})
+	if got := tensor.At(1, 2); got != int32(6) {
+		t.Errorf("Got %v, want 6", got)
+	}
+	if got := mustTensor(t, [][]string{{"a", "b"}, {"c", "d"}}).At(1, 0); got != "c" {
+		t.Errorf("Got %q, want %q", got, "c")
+	}
+	if got := mustTensor(t, float64(1.5)).At(); got != 1.5 {
+		t.Errorf("Got %v, want 1.5", got)
+	}
+	for _, idx := range [][]int64{{1}, {2, 0}, {0, -1}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("At(%v) did not panic", idx)
+				}
+			}()
+			tensor.At(idx...)
+		}()
+	}
+}
+
+func TestTensorSlice(t *testing.T) {
+	tensor := mustTensor(t, [][]int64{{1, 2, 3}, {4, 5, 6}})
+	tests := []struct {
+		dim        int
+		start, end int64
+		want       interface{}
+	}{
+		{0, 1, 2, [][]int64{{4, 5, 6}}},
+		{1, 1, 3, [][]int64{{2, 3}, {5, 6}}},
+		{1, 2, 2, [][]int64{{}, {}}},
+	}
+	for _, test := range tests {
+		s, err := tensor.Slice(test.dim, test.start, test.end)
+		if err != nil {
+			t.Errorf("Slice(%d, %d, %d): %v", test.dim, test.start, test.end, err)
+			continue
+		}
+		if got := s.Value(); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Slice(%d, %d, %d) = %v, want %v", test.dim, test.start, test.end, got, test.want)
+		}
+	}
+	if _, err := tensor.Slice(2, 0, 1); err == nil {
+		t.Errorf("Slice of an invalid dimension did not fail")
+	}
+	if _, err := tensor.Slice(0, 1, 3); err == nil {
+		t.Errorf("Slice out of range did not fail")
+	}
+
+	strs, err := mustTensor(t, []string{"a", "b", "c"}).Slice(0, 1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strs.Value(), []string{"b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %v, want %v", got, want)
+	}
+}
+
+func TestTensorSplitStack(t *testing.T) {
+	value := [][]float32{{1, 2}, {3, 4}, {5, 6}}
+	tensor := mustTensor(t, value)
+	rows, err := tensor.Split(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("Got %d tensors, want 3", len(rows))
+	}
+	for i, row := range rows {
+		if got := row.Value(); !reflect.DeepEqual(got, value[i]) {
+			t.Errorf("Got row %d = %v, want %v", i, got, value[i])
+		}
+	}
+	stacked, err := Stack(0, rows...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := stacked.Value(); !reflect.DeepEqual(got, value) {
+		t.Errorf("Got %v, want %v", got, value)
+	}
+
+	columns, err := tensor.Split(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := columns[1].Value(), []float32{2, 4, 6}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %v, want %v", got, want)
+	}
+	stacked, err = Stack(1, columns...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := stacked.Value(); !reflect.DeepEqual(got, value) {
+		t.Errorf("Got %v, want %v", got, value)
+	}
+
+	// scalars are stacked into a vector
+	scalars, err := mustTensor(t, []string{"x", "y"}).Split(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := scalars[1].Value(); got != "y" {
+		t.Errorf("Got %v, want %q", got, "y")
+	}
+	if _, err := Stack(0, rows[0], columns[0]); err == nil {
+		t.Errorf("Stack of different shapes did not fail")
+	}
+}
+
+func TestConcat(t *testing.T) {
+	a := mustTensor(t, [][]int32{{1, 2}, {3, 4}})
+	b := mustTensor(t, [][]int32{{5}, {6}})
+	joined, err := Concat(1, a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := joined.Value(), [][]int32{{1, 2, 5}, {3, 4, 6}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %v, want %v", got, want)
+	}
+	joined, err = Concat(0, a, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := joined.Value(), [][]int32{{1, 2}, {3, 4}, {1, 2}, {3, 4}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %v, want %v", got, want)
+	}
+	if _, err := Concat(0, a, b); err == nil {
+		t.Errorf("Concat of mismatching shapes did not fail")
+	}
+	if _, err := Concat(0, a, mustTensor(t, [][]int64{{1, 2}})); err == nil {
+		t.Errorf("Concat of mismatching types did not fail")
+	}
+
+	strs, err := Concat(0, mustTensor(t, []string{"a"}), mustTensor(t, []string{"b", "c"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strs.Value(), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %v, want %v", got, want)
+	}
+}
+
+func BenchmarkTensorSplitStack(b *testing.B) {
+	batch := mustTensor(b, [64][256]float32{})
+	for i := 0; i < b.N; i++ {
+		rows, err := batch.Split(0)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := Stack(0, rows...); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
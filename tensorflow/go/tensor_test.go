@@ -44,6 +44,12 @@ func TestNewTensor(t *testing.T) {
 		{nil, complex(float32(5), float32(6))},
 		{nil, complex(float64(5), float64(6))},
 		{nil, "a string"},
+		{nil, NewFloat16(1.5)},
+		{nil, NewBFloat16(-2.5)},
+		{nil, NewFloat8E5M2(3)},
+		{nil, NewFloat8E4M3FN(0.5)},
+		{[]int64{2}, []Float16{NewFloat16(1), NewFloat16(-65504)}},
+		{[]int64{1, 2}, [][]BFloat16{{NewBFloat16(1), NewBFloat16(3e38)}}},
 		{[]int64{1}, []uint32{1}},
 		{[]int64{1}, []uint64{1}},
 		{[]int64{2}, []bool{true, false}},